@@ -0,0 +1,212 @@
+package docling
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+const (
+	archiveVersion   = 1
+	archiveManifest  = "manifest.json"
+	archiveContents  = "contents/"
+	archiveArtifacts = "artifacts/"
+)
+
+// manifest is the index of an archive. The responses are stored with empty
+// documents, the contents of the documents being separate blobs.
+type manifest struct {
+	Version   int                `json:"version"`
+	Responses []manifestResponse `json:"responses"`
+}
+
+type manifestResponse struct {
+	Response json.RawMessage   `json:"response"`
+	Filename string            `json:"filename"`
+	Contents []manifestContent `json:"contents"`
+}
+
+type manifestContent struct {
+	Format ToFormat `json:"format"`
+	Blob   string   `json:"blob"`
+	JSON   bool     `json:"json,omitempty"` // RawContent holding JSON
+	// Artifacts is set when the embedded images were moved to artifacts
+	Artifacts bool `json:"artifacts,omitempty"`
+}
+
+// WriteArchive writes the responses as a zip archive of a JSON manifest and
+// content blobs. Identical contents are stored once, and the images embedded
+// in the contents are stored once as separate artifacts.
+func WriteArchive(w io.Writer, resps ...ConvertResponse) error {
+	ctx := context.Background()
+	zw := zip.NewWriter(w)
+	written := make(map[string]bool)
+	writeBlob := func(name string, data []byte, method uint16) error {
+		if written[name] {
+			return nil
+		}
+		written[name] = true
+		f, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: method})
+		if err != nil {
+			return err
+		}
+		_, err = f.Write(data)
+		return err
+	}
+	m := manifest{Version: archiveVersion}
+	for _, resp := range resps {
+		doc := resp.Document
+		resp.Document = Document{}
+		data, err := json.Marshal(resp)
+		if err != nil {
+			return err
+		}
+		mr := manifestResponse{Response: data, Filename: doc.Filename}
+		for _, content := range doc.Contents {
+			mc := manifestContent{Format: content.Format()}
+			if rc, ok := content.(RawContent); ok {
+				mc.JSON = rc.JSON
+			}
+			store := NewMemoryArtifactStore()
+			s, err := extractArchiveArtifacts(ctx, store, content)
+			if err != nil {
+				return err
+			}
+			mc.Artifacts = len(store.artifacts) > 0
+			for name, data := range store.artifacts {
+				// images are already compressed
+				err = writeBlob(archiveArtifacts+name, data, zip.Store)
+				if err != nil {
+					return err
+				}
+			}
+			sum := sha256.Sum256([]byte(s))
+			ext, err := contentExt(content.Format())
+			if err != nil {
+				return err
+			}
+			mc.Blob = archiveContents + hex.EncodeToString(sum[:]) + ext
+			err = writeBlob(mc.Blob, []byte(s), zip.Deflate)
+			if err != nil {
+				return err
+			}
+			mr.Contents = append(mr.Contents, mc)
+		}
+		m.Responses = append(m.Responses, mr)
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	err = writeBlob(archiveManifest, data, zip.Deflate)
+	if err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// extractArchiveArtifacts moves the images of a content to store, keeping
+// them embedded when inlining them back would not restore the content as is.
+func extractArchiveArtifacts(ctx context.Context, store *MemoryArtifactStore, content Content) (string, error) {
+	s, err := readContent(content)
+	if err != nil {
+		return "", err
+	}
+	if sc, ok := content.(*SpooledContent); ok {
+		content = newContent(sc.Format(), []byte(s))
+	}
+	original := Document{Contents: []Content{content}}
+	extracted, err := ExtractArtifacts(ctx, store, original)
+	if err != nil {
+		return "", err
+	}
+	if len(store.artifacts) == 0 {
+		return s, nil
+	}
+	inlined, err := InlineArtifacts(ctx, store, extracted)
+	if err != nil {
+		return "", err
+	}
+	if inlined.Contents[0].String() != s {
+		clear(store.artifacts)
+		return s, nil
+	}
+	return extracted.Contents[0].String(), nil
+}
+
+// ReadArchive reads the responses of an archive written by WriteArchive.
+func ReadArchive(r io.ReaderAt, size int64) ([]ConvertResponse, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive: %w", err)
+	}
+	readFile := func(name string) ([]byte, error) {
+		f, err := zr.Open(name)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		return io.ReadAll(f)
+	}
+	data, err := readFile(archiveManifest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive manifest: %w", err)
+	}
+	var m manifest
+	err = json.Unmarshal(data, &m)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode archive manifest: %w", err)
+	}
+	if m.Version != archiveVersion {
+		return nil, fmt.Errorf("unsupported archive version %d", m.Version)
+	}
+	store := NewMemoryArtifactStore()
+	for _, f := range zr.File {
+		name, ok := strings.CutPrefix(f.Name, archiveArtifacts)
+		if !ok || name == "" || path.Clean(name) != name {
+			continue
+		}
+		data, err := readFile(f.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", f.Name, err)
+		}
+		store.artifacts[name] = data
+	}
+	resps := make([]ConvertResponse, 0, len(m.Responses))
+	for _, mr := range m.Responses {
+		var resp ConvertResponse
+		err = json.Unmarshal(mr.Response, &resp)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode response of %s: %w", mr.Filename, err)
+		}
+		resp.Document = Document{Filename: mr.Filename}
+		for _, mc := range mr.Contents {
+			data, err := readFile(mc.Blob)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s: %w", mc.Blob, err)
+			}
+			var content Content
+			if mc.JSON {
+				content = RawContent{ContentFormat: mc.Format, Data: string(data), JSON: true}
+			} else {
+				content = newContent(mc.Format, data)
+			}
+			if mc.Artifacts {
+				doc, err := InlineArtifacts(context.Background(), store, Document{Contents: []Content{content}})
+				if err != nil {
+					return nil, err
+				}
+				content = doc.Contents[0]
+			}
+			resp.Document.Contents = append(resp.Document.Contents, content)
+		}
+		resps = append(resps, resp)
+	}
+	return resps, nil
+}
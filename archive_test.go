@@ -0,0 +1,59 @@
+package docling
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestArchiveRoundTrip(t *testing.T) {
+	uri := "data:image/png;base64," + base64.StdEncoding.EncodeToString([]byte("\x89PNG\r\n\x1a\nimage"))
+	resp := ConvertResponse{
+		Status:         "success",
+		ProcessingTime: 2.5,
+		Document: Document{
+			Filename: "report.pdf",
+			Contents: []Content{
+				MarkdownContent("# Title\n\n![Image](" + uri + ")"),
+				JSONContent(`{"pictures":[{"image":{"uri":"` + uri + `"}}]}`),
+				TextContent("Title"),
+				RawContent{ContentFormat: "graph", Data: `{"nodes":[]}`, JSON: true},
+			},
+		},
+	}
+	copied := resp
+	copied.Document.Filename = "copy.pdf"
+	var b bytes.Buffer
+	err := WriteArchive(&b, resp, copied)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Count(b.Bytes(), []byte("\x89PNG")) != 1 {
+		t.Fatal("expected the image to be stored once")
+	}
+	resps, err := ReadArchive(bytes.NewReader(b.Bytes()), int64(b.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resps) != 2 {
+		t.Fatalf("expected 2 responses, got %d", len(resps))
+	}
+	for i, want := range []ConvertResponse{resp, copied} {
+		got, err := json.Marshal(resps[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected, err := json.Marshal(want)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, expected) {
+			t.Fatalf("expected %s, got %s", expected, got)
+		}
+		if !reflect.DeepEqual(resps[i].Document.Contents, want.Document.Contents) {
+			t.Fatalf("expected contents %#v, got %#v", want.Document.Contents, resps[i].Document.Contents)
+		}
+	}
+}
@@ -0,0 +1,334 @@
+package docling
+
+import (
+	"archive/zip"
+	"bytes"
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"io/fs"
+	"mime"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// ArtifactStore stores the images of conversion results out of the documents.
+type ArtifactStore interface {
+	// Put stores the artifact and returns the URL the documents reference it by.
+	Put(ctx context.Context, name string, data []byte) (string, error)
+	// Get returns the artifact referenced by ref, a name or URL returned by Put.
+	Get(ctx context.Context, ref string) ([]byte, error)
+}
+
+// DirArtifactStore stores artifacts as files of a directory, referenced by
+// baseURL/name, or by their relative path when baseURL is empty.
+type DirArtifactStore struct {
+	dir     string
+	baseURL string
+}
+
+func NewDirArtifactStore(dir, baseURL string) (*DirArtifactStore, error) {
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create artifact directory: %w", err)
+	}
+	return &DirArtifactStore{dir: dir, baseURL: strings.TrimSuffix(baseURL, "/")}, nil
+}
+
+func (s *DirArtifactStore) Put(ctx context.Context, name string, data []byte) (string, error) {
+	p, err := s.path(name)
+	if err != nil {
+		return "", err
+	}
+	err = os.MkdirAll(filepath.Dir(p), 0o755)
+	if err != nil {
+		return "", err
+	}
+	err = os.WriteFile(p, data, 0o644)
+	if err != nil {
+		return "", err
+	}
+	if s.baseURL == "" {
+		return name, nil
+	}
+	return s.baseURL + "/" + name, nil
+}
+
+func (s *DirArtifactStore) Get(ctx context.Context, ref string) ([]byte, error) {
+	if s.baseURL != "" {
+		ref = strings.TrimPrefix(ref, s.baseURL+"/")
+	}
+	if u, err := url.Parse(ref); err != nil || u.Scheme != "" {
+		// URLs out of the base URL do not reference the directory
+		return nil, fmt.Errorf("artifact %q: %w", ref, fs.ErrNotExist)
+	}
+	p, err := s.path(ref)
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(p)
+}
+
+func (s *DirArtifactStore) path(name string) (string, error) {
+	if !filepath.IsLocal(filepath.FromSlash(name)) {
+		return "", fmt.Errorf("invalid artifact name %q: %w", name, fs.ErrNotExist)
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(name)), nil
+}
+
+// MemoryArtifactStore keeps artifacts in memory, referenced by their name.
+type MemoryArtifactStore struct {
+	mu        sync.Mutex
+	artifacts map[string][]byte
+}
+
+func NewMemoryArtifactStore() *MemoryArtifactStore {
+	return &MemoryArtifactStore{artifacts: make(map[string][]byte)}
+}
+
+func (s *MemoryArtifactStore) Put(ctx context.Context, name string, data []byte) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.artifacts[name] = data
+	return name, nil
+}
+
+func (s *MemoryArtifactStore) Get(ctx context.Context, ref string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, ok := s.artifacts[ref]
+	if !ok {
+		return nil, fmt.Errorf("artifact %q: %w", ref, os.ErrNotExist)
+	}
+	return data, nil
+}
+
+var dataURIRegexp = regexp.MustCompile(`data:image/[a-zA-Z0-9.+-]+;base64,[A-Za-z0-9+/=]+`)
+
+// imageExts maps image MIME types to file extensions.
+var imageExts = map[string]string{
+	"image/png":     ".png",
+	"image/jpeg":    ".jpg",
+	"image/gif":     ".gif",
+	"image/webp":    ".webp",
+	"image/tiff":    ".tiff",
+	"image/bmp":     ".bmp",
+	"image/svg+xml": ".svg",
+}
+
+// ExtractArtifacts moves the images embedded as data URIs in the contents of
+// the document to the store, and references them by the returned URLs. Images
+// are named after their hash so each one is stored once.
+func ExtractArtifacts(ctx context.Context, store ArtifactStore, doc Document) (Document, error) {
+	urls := make(map[string]string)
+	return rewriteContents(doc, func(format ToFormat, s string) (string, error) {
+		var err error
+		s = dataURIRegexp.ReplaceAllStringFunc(s, func(uri string) string {
+			if err != nil {
+				return uri
+			}
+			u, ok := urls[uri]
+			if !ok {
+				var (
+					mimeType string
+					data     []byte
+				)
+				mimeType, data, _, err = decodeDataURI(uri)
+				if err != nil {
+					return uri
+				}
+				sum := sha256.Sum256(data)
+				name := "image_" + hex.EncodeToString(sum[:8]) + imageExts[mimeType]
+				u, err = store.Put(ctx, name, data)
+				if err != nil {
+					err = fmt.Errorf("failed to store artifact %s: %w", name, err)
+					return uri
+				}
+				urls[uri] = u
+			}
+			return escapeRef(format, u)
+		})
+		return s, err
+	})
+}
+
+// ExtractZipArtifacts decodes a zip result, as returned for TargetZip or
+// uploaded to a TargetPut in referenced image mode. The files which are not
+// exported contents are moved to the store, and the references of the
+// documents are rewritten to the returned URLs.
+func ExtractZipArtifacts(ctx context.Context, store ArtifactStore, data []byte) ([]ConvertResponse, error) {
+	resps, err := DecodeResult(data)
+	if err != nil {
+		return nil, err
+	}
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open zip result: %w", err)
+	}
+	urls := make(map[string]string)
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() || isZipContent(f.Name) {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s: %w", f.Name, err)
+		}
+		content, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", f.Name, err)
+		}
+		urls[f.Name], err = store.Put(ctx, f.Name, content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to store artifact %s: %w", f.Name, err)
+		}
+	}
+	for i, resp := range resps {
+		resps[i].Document, err = rewriteContents(resp.Document, func(format ToFormat, s string) (string, error) {
+			return replaceRefs(s, func(ref string) (string, bool) {
+				u, ok := urls[unescapeRef(format, ref)]
+				return escapeRef(format, u), ok
+			}), nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return resps, nil
+}
+
+// InlineArtifacts embeds the images the document references as data URIs,
+// making it self-contained. References unknown to the store are left as is.
+func InlineArtifacts(ctx context.Context, store ArtifactStore, doc Document) (Document, error) {
+	uris := make(map[string]string)
+	return rewriteContents(doc, func(format ToFormat, s string) (string, error) {
+		var err error
+		s = replaceRefs(s, func(ref string) (string, bool) {
+			if err != nil || strings.HasPrefix(ref, "data:") {
+				return "", false
+			}
+			ref = unescapeRef(format, ref)
+			uri, ok := uris[ref]
+			if !ok {
+				data, gerr := store.Get(ctx, ref)
+				if errors.Is(gerr, fs.ErrNotExist) {
+					return "", false
+				}
+				if gerr != nil {
+					err = fmt.Errorf("failed to get artifact %s: %w", ref, gerr)
+					return "", false
+				}
+				mimeType := cmp.Or(mime.TypeByExtension(path.Ext(ref)), "application/octet-stream")
+				uri = "data:" + mimeType + ";base64," + base64.StdEncoding.EncodeToString(data)
+				uris[ref] = uri
+			}
+			return uri, true
+		})
+		return s, err
+	})
+}
+
+// refRegexps match the image references of markdown, HTML and JSON contents,
+// the reference being the first submatch.
+var refRegexps = []*regexp.Regexp{
+	regexp.MustCompile(`!\[[^\]]*\]\(([^)\s]+)\)`),
+	regexp.MustCompile(`<img\b[^>]*\bsrc="([^"]+)"`),
+	regexp.MustCompile(`"uri"\s*:\s*"([^"]+)"`),
+}
+
+// replaceRefs replaces the image references of s for which fn returns true.
+func replaceRefs(s string, fn func(ref string) (string, bool)) string {
+	for _, re := range refRegexps {
+		s = re.ReplaceAllStringFunc(s, func(m string) string {
+			loc := re.FindStringSubmatchIndex(m)
+			repl, ok := fn(m[loc[2]:loc[3]])
+			if !ok {
+				return m
+			}
+			return m[:loc[2]] + repl + m[loc[3]:]
+		})
+	}
+	return s
+}
+
+// markdownRefEscaper percent-encodes the characters ending a markdown link
+// destination, and the percent sign so escaped references can be decoded.
+var (
+	markdownRefEscaper   = strings.NewReplacer("%", "%25", " ", "%20", "(", "%28", ")", "%29", "\t", "%09", "\n", "%0A")
+	markdownRefUnescaper = strings.NewReplacer("%25", "%", "%20", " ", "%28", "(", "%29", ")", "%09", "\t", "%0A", "\n")
+)
+
+// escapeRef escapes a reference for the syntax of the given format.
+func escapeRef(format ToFormat, ref string) string {
+	switch format {
+	case ToMarkdown:
+		return markdownRefEscaper.Replace(ref)
+	case ToHTML:
+		return html.EscapeString(ref)
+	case ToJSON:
+		data, _ := json.Marshal(ref)
+		return string(data[1 : len(data)-1])
+	}
+	return ref
+}
+
+// unescapeRef reverses escapeRef.
+func unescapeRef(format ToFormat, ref string) string {
+	switch format {
+	case ToMarkdown:
+		return markdownRefUnescaper.Replace(ref)
+	case ToHTML:
+		return html.UnescapeString(ref)
+	case ToJSON:
+		var s string
+		if json.Unmarshal([]byte(`"`+ref+`"`), &s) == nil {
+			return s
+		}
+	}
+	return ref
+}
+
+// rewriteContents returns a copy of the document with its contents rewritten
+// by fn.
+func rewriteContents(doc Document, fn func(format ToFormat, s string) (string, error)) (Document, error) {
+	out := Document{Filename: doc.Filename, Contents: make([]Content, 0, len(doc.Contents))}
+	for _, content := range doc.Contents {
+		s, err := readContent(content)
+		if err != nil {
+			return Document{}, err
+		}
+		s, err = fn(content.Format(), s)
+		if err != nil {
+			return Document{}, err
+		}
+		if rc, ok := content.(RawContent); ok {
+			rc.Data = s
+			out.Contents = append(out.Contents, rc)
+			continue
+		}
+		out.Contents = append(out.Contents, newContent(content.Format(), []byte(s)))
+	}
+	return out, nil
+}
+
+func isZipContent(name string) bool {
+	for _, cf := range zipContentFormats {
+		if strings.HasSuffix(name, cf.ext) {
+			return true
+		}
+	}
+	return false
+}
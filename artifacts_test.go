@@ -0,0 +1,115 @@
+package docling
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestArtifactsRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	png := []byte("\x89PNG\r\n\x1a\nfake")
+	uri := "data:image/png;base64," + base64.StdEncoding.EncodeToString(png)
+	doc := Document{
+		Filename: "doc.pdf",
+		Contents: []Content{
+			MarkdownContent("# Title\n\n![Image](" + uri + ")\n"),
+			HTMLContent(`<p><img src="` + uri + `"></p>`),
+			JSONContent(`{"pictures":[{"image":{"mimetype":"image/png","uri":"` + uri + `"}}]}`),
+		},
+	}
+	store := NewMemoryArtifactStore()
+	extracted, err := ExtractArtifacts(ctx, store, doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(store.artifacts) != 1 {
+		t.Fatalf("expected the image to be stored once, got %d artifacts", len(store.artifacts))
+	}
+	for _, content := range extracted.Contents {
+		if strings.Contains(content.String(), "data:") || !strings.Contains(content.String(), ".png") {
+			t.Fatalf("expected %s content to reference the stored image, got %q", content.Format(), content.String())
+		}
+	}
+	inlined, err := InlineArtifacts(ctx, store, extracted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, content := range inlined.Contents {
+		if content.String() != doc.Contents[i].String() {
+			t.Fatalf("expected %q, got %q", doc.Contents[i].String(), content.String())
+		}
+	}
+}
+
+func TestExtractZipArtifacts(t *testing.T) {
+	var b bytes.Buffer
+	zw := zip.NewWriter(&b)
+	for name, content := range map[string]string{
+		"doc.md":                     "![Image](artifacts/image_000000.png)",
+		"doc.html":                   `<img src="artifacts/image_000000.png">`,
+		"artifacts/image_000000.png": "png",
+	} {
+		f, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Write([]byte(content))
+	}
+	zw.Close()
+	dir := t.TempDir()
+	store, err := NewDirArtifactStore(dir, "https://cdn.example.com/a?x=1&y=2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resps, err := ExtractZipArtifacts(context.Background(), store, b.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := resps[0].Document
+	const url = "https://cdn.example.com/a?x=1&y=2/artifacts/image_000000.png"
+	if md := doc.MarkdownContent(); md != "![Image]("+url+")" {
+		t.Fatalf("unexpected markdown %q", md)
+	}
+	if h := doc.HTMLContent(); h != `<img src="https://cdn.example.com/a?x=1&amp;y=2/artifacts/image_000000.png">` {
+		t.Fatalf("unexpected html %q", h)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "artifacts", "image_000000.png"))
+	if err != nil || string(data) != "png" {
+		t.Fatalf("expected the artifact to be written, got %q, %v", data, err)
+	}
+	_, err = store.Put(context.Background(), "../escape.png", nil)
+	if err == nil {
+		t.Fatal("expected an error for a name outside of the directory")
+	}
+}
+
+func TestInlineArtifactsUnknownRefs(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewDirArtifactStore(t.TempDir(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ref, err := store.Put(ctx, "chart (1).png", []byte("png"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	md := "![Chart](" + escapeRef(ToMarkdown, ref) + ")"
+	if md != "![Chart](chart%20%281%29.png)" {
+		t.Fatalf("unexpected markdown %q", md)
+	}
+	const unknown = "![Logo](/static/logo.png) ![Remote](https://example.com/image.png) ![Parent](../image.png)"
+	doc, err := InlineArtifacts(ctx, store, Document{Contents: []Content{MarkdownContent(md + " " + unknown)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "![Chart](data:image/png;base64," + base64.StdEncoding.EncodeToString([]byte("png")) + ") " + unknown
+	if got := doc.MarkdownContent(); got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,271 @@
+package docling
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Cache stores encoded conversion results. Implementations only deal with
+// opaque bytes so they can be backed by anything able to store a blob under a
+// key (memory, files, Redis, SQL...).
+type Cache interface {
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte) error
+}
+
+// WithCache serves conversions from cache when the same files or sources are
+// converted with the same options. HTTP sources are keyed by their URL and
+// headers, not by their content: a remote document which changed is still
+// served from the cache, so use a Cache expiring its entries (or none) for
+// sources which are updated in place.
+func WithCache(cache Cache) ClientOption {
+	return func(c *Client) {
+		c.cache = cache
+	}
+}
+
+type MemoryCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	ll         list.List
+	items      map[string]*list.Element
+}
+
+type memoryCacheEntry struct {
+	key   string
+	value []byte
+}
+
+func NewMemoryCache(maxEntries int) *MemoryCache {
+	return &MemoryCache{
+		maxEntries: maxEntries,
+		items:      make(map[string]*list.Element),
+	}
+}
+
+func (mc *MemoryCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	e, ok := mc.items[key]
+	if !ok {
+		return nil, false, nil
+	}
+	mc.ll.MoveToFront(e)
+	return e.Value.(*memoryCacheEntry).value, true, nil
+}
+
+func (mc *MemoryCache) Set(ctx context.Context, key string, value []byte) error {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	if e, ok := mc.items[key]; ok {
+		e.Value.(*memoryCacheEntry).value = value
+		mc.ll.MoveToFront(e)
+		return nil
+	}
+	mc.items[key] = mc.ll.PushFront(&memoryCacheEntry{key: key, value: value})
+	for mc.maxEntries > 0 && mc.ll.Len() > mc.maxEntries {
+		oldest := mc.ll.Back()
+		mc.ll.Remove(oldest)
+		delete(mc.items, oldest.Value.(*memoryCacheEntry).key)
+	}
+	return nil
+}
+
+type DirCache struct {
+	dir string
+}
+
+func NewDirCache(dir string) (*DirCache, error) {
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	return &DirCache{dir: dir}, nil
+}
+
+func (dc *DirCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	data, err := os.ReadFile(dc.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return data, true, nil
+}
+
+func (dc *DirCache) Set(ctx context.Context, key string, value []byte) error {
+	f, err := os.CreateTemp(dc.dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(value)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	// rename is atomic so concurrent readers never see a partial entry
+	return os.Rename(f.Name(), dc.path(key))
+}
+
+func (dc *DirCache) path(key string) string {
+	return filepath.Join(dc.dir, key+".json")
+}
+
+// encodeCacheEntry encodes a response in the wire format of the server, so
+// that entries decode like responses.
+func encodeCacheEntry(resp ConvertResponse) ([]byte, error) {
+	return json.Marshal(resp)
+}
+
+func decodeCacheEntry(data []byte) (ConvertResponse, error) {
+	var resp ConvertResponse
+	err := json.Unmarshal(data, &resp)
+	if err != nil {
+		return ConvertResponse{}, err
+	}
+	return resp, nil
+}
+
+// cacheSpoolThreshold is the size above which the copies of the files made to
+// hash them are written to temporary files.
+const cacheSpoolThreshold = 4 << 20
+
+// fileCacheKey hashes the files content. Seekable files are rewound after
+// hashing, the others are copied while hashed and the returned request uploads
+// the copies, which are removed by the returned cleanup function.
+func fileCacheKey(req ProcessFileRequest) (string, ProcessFileRequest, func(), error) {
+	var spools []*spool
+	cleanup := func() {
+		for _, s := range spools {
+			s.discard()
+		}
+	}
+	h := sha256.New()
+	fmt.Fprintf(h, "file\x00%s\x00", req.TargetType)
+	err := writeOptionsKey(h, req.ConvertOptions)
+	if err != nil {
+		return "", req, cleanup, err
+	}
+	files := make([]File, len(req.Files))
+	for i, f := range req.Files {
+		files[i] = f
+		fh := sha256.New()
+		var r io.Reader = f
+		if fr, ok := f.(FileReader); ok {
+			r = fr.Reader
+		}
+		if rs, ok := r.(io.ReadSeeker); ok {
+			err = hashSeeker(fh, rs)
+		} else {
+			s := &spool{cfg: &spoolConfig{threshold: cacheSpoolThreshold}}
+			spools = append(spools, s)
+			_, err = io.Copy(io.MultiWriter(fh, s), f)
+			files[i] = s.file(f.Name())
+		}
+		if err != nil {
+			cleanup()
+			return "", req, func() {}, fmt.Errorf("failed to read file %q: %w", f.Name(), err)
+		}
+		fmt.Fprintf(h, "%s\x00%x\x00", filepath.Base(f.Name()), fh.Sum(nil))
+	}
+	req.Files = files
+	return hex.EncodeToString(h.Sum(nil)), req, cleanup, nil
+}
+
+// hashSeeker hashes rs from its current offset, then seeks back to it.
+func hashSeeker(w io.Writer, rs io.ReadSeeker) error {
+	offset, err := rs.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, rs)
+	if err != nil {
+		return err
+	}
+	_, err = rs.Seek(offset, io.SeekStart)
+	return err
+}
+
+// urlCacheKey returns an empty key when the request cannot be cached because
+// hashing it would consume its sources.
+func urlCacheKey(req ProcessURLRequest) (string, error) {
+	if !req.replayable() {
+		return "", nil
+	}
+	h := sha256.New()
+	fmt.Fprint(h, "source\x00")
+	err := writeOptionsKey(h, req.Options)
+	if err != nil {
+		return "", err
+	}
+	// sources are hashed as they are sent, file sources are streamed from
+	// their reader rather than loaded in memory
+	req.Options = ConvertOptions{}
+	err = req.writeJSON(h)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func writeOptionsKey(w io.Writer, opts ConvertOptions) error {
+	data, err := opts.Canonical()
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write([]byte{0})
+	return err
+}
+
+func (c *Client) cacheGet(ctx context.Context, key string) (ConvertResponse, bool) {
+	data, ok, err := c.cache.Get(ctx, key)
+	if err != nil {
+		c.logger.WarnContext(ctx, "failed to get conversion from cache", slog.String("key", key), slog.Any("error", err))
+		return ConvertResponse{}, false
+	}
+	if !ok {
+		return ConvertResponse{}, false
+	}
+	resp, err := decodeCacheEntry(data)
+	if err != nil {
+		c.logger.WarnContext(ctx, "failed to decode cached conversion", slog.String("key", key), slog.Any("error", err))
+		return ConvertResponse{}, false
+	}
+	resp.CacheHit = true
+	return resp, true
+}
+
+func (c *Client) cacheSet(ctx context.Context, key string, resp ConvertResponse) {
+	// only cache complete results, anything else is worth retrying
+	if resp.Status != "success" {
+		return
+	}
+	data, err := encodeCacheEntry(resp)
+	if err != nil {
+		c.logger.WarnContext(ctx, "failed to encode conversion for cache", slog.String("key", key), slog.Any("error", err))
+		return
+	}
+	err = c.cache.Set(ctx, key, data)
+	if err != nil {
+		c.logger.WarnContext(ctx, "failed to store conversion in cache", slog.String("key", key), slog.Any("error", err))
+	}
+}
@@ -0,0 +1,78 @@
+package docling
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestProcessFileCache(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Write([]byte(`{"document":{"filename":"doc.pdf","md_content":"# Title","json_content":{"name":"doc"}},"status":"success"}`))
+	}))
+	defer srv.Close()
+	dir, err := NewDirCache(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, cache := range []Cache{NewMemoryCache(1), dir} {
+		calls = 0
+		c, err := NewClient(ClientConfig{BaseURL: srv.URL}, WithCache(cache))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := range 2 {
+			resp, err := c.ProcessFile(context.Background(), ProcessFileRequest{
+				Files: []File{FileReader{Filename: "doc.pdf", Reader: bytes.NewReader([]byte("%PDF-1.7"))}},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.CacheHit != (i == 1) {
+				t.Fatalf("request %d: unexpected cache hit: %t", i, resp.CacheHit)
+			}
+			if got := resp.Document.MarkdownContent(); got != "# Title" {
+				t.Fatalf("unexpected markdown content: %q", got)
+			}
+			if got := resp.Document.JSONContent(); got != `{"name":"doc"}` {
+				t.Fatalf("unexpected json content: %q", got)
+			}
+		}
+		if calls != 1 {
+			t.Fatalf("expected 1 call to the server, got %d", calls)
+		}
+	}
+}
+
+func TestFileCacheKey(t *testing.T) {
+	content := strings.Repeat("%PDF-1.7\n", 1<<20)
+	seeker := strings.NewReader("skipped" + content)
+	seeker.Seek(int64(len("skipped")), io.SeekStart)
+	var keys []string
+	for _, r := range []io.Reader{seeker, io.MultiReader(strings.NewReader(content))} {
+		key, req, cleanup, err := fileCacheKey(ProcessFileRequest{
+			Files: []File{FileReader{Filename: "doc.pdf", Reader: r}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer cleanup()
+		data, err := io.ReadAll(req.Files[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != content {
+			t.Fatal("file content changed by hashing")
+		}
+		keys = append(keys, key)
+	}
+	if keys[0] != keys[1] {
+		t.Fatalf("expected the same key for seekable and streamed files, got %s and %s", keys[0], keys[1])
+	}
+}
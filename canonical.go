@@ -0,0 +1,164 @@
+package docling
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"slices"
+)
+
+const (
+	defaultPictureDescriptionPrompt = "Describe this image in a few sentences."
+	defaultVLMPrompt                = "Convert this page to docling."
+)
+
+var allFromFormats = []FromFormat{
+	FromDOCX,
+	FromPPTX,
+	FromHTML,
+	FromImage,
+	FromPDF,
+	FromASCIIDoc,
+	FromMarkdown,
+	FromCSV,
+	FromXLSX,
+	FromXMLUspto,
+	FromXMLJats,
+	FromMetsGbs,
+	FromJSONDocling,
+	FromAudio,
+}
+
+// WithDefaults returns a copy of the options where every unset field holds the
+// value docling-serve would use in its place.
+func (o ConvertOptions) WithDefaults() ConvertOptions {
+	if len(o.FromFormats) == 0 {
+		o.FromFormats = slices.Clone(allFromFormats)
+	}
+	if len(o.ToFormats) == 0 {
+		o.ToFormats = []ToFormat{ToMarkdown}
+	}
+	if o.ImageExportMode == "" {
+		o.ImageExportMode = ImageExportModeEmbedded
+	}
+	if o.DoOCR == nil {
+		o.DoOCR = Ptr(true)
+	}
+	if o.OCREngine == "" {
+		o.OCREngine = OCREngineEasyOCR
+	}
+	if o.PDFBackend == "" {
+		o.PDFBackend = PDFBackendDLParseV4
+	}
+	if o.TableMode == "" {
+		o.TableMode = TableModeAccurate
+	}
+	if o.TableCellMatching == nil {
+		o.TableCellMatching = Ptr(true)
+	}
+	if o.Pipeline == "" {
+		o.Pipeline = PipelineStandard
+	}
+	if o.PageRange.From == 0 {
+		o.PageRange.From = 1
+	}
+	if o.DocumentTimeout == nil {
+		o.DocumentTimeout = Ptr(604800)
+	}
+	if o.DoTableStructure == nil {
+		o.DoTableStructure = Ptr(true)
+	}
+	if o.IncludeImages == nil {
+		o.IncludeImages = Ptr(true)
+	}
+	if o.ImagesScale == nil {
+		o.ImagesScale = Ptr(2.0)
+	}
+	if o.PictureDescriptionAreaThreshold == nil {
+		o.PictureDescriptionAreaThreshold = Ptr(0.05)
+	}
+	if o.PictureDescriptionLocal != nil {
+		local := *o.PictureDescriptionLocal
+		if local.GenerationConfig == (GenerationConfig{}) {
+			local.GenerationConfig = GenerationConfig{MaxNewTokens: 200}
+		}
+		if local.Prompt == "" {
+			local.Prompt = defaultPictureDescriptionPrompt
+		}
+		o.PictureDescriptionLocal = &local
+	}
+	if o.PictureDescriptionAPI != nil {
+		api := *o.PictureDescriptionAPI
+		if api.Concurrency == 0 {
+			api.Concurrency = 1
+		}
+		if api.Prompt == "" {
+			api.Prompt = defaultPictureDescriptionPrompt
+		}
+		if api.Timeout == 0 {
+			api.Timeout = 20
+		}
+		o.PictureDescriptionAPI = &api
+	}
+	if o.VLMPipelineModelLocal != nil {
+		local := *o.VLMPipelineModelLocal
+		if local.ExtraGenerationConfig == nil {
+			local.ExtraGenerationConfig = map[string]any{"max_new_tokens": 800, "do_sample": false}
+		}
+		if local.Prompt == "" {
+			local.Prompt = defaultVLMPrompt
+		}
+		if local.Scale == 0 {
+			local.Scale = 2.0
+		}
+		if local.TransformersModelType == "" {
+			local.TransformersModelType = TransformersModelTypeAutoModel
+		}
+		o.VLMPipelineModelLocal = &local
+	}
+	if o.VLMPipelineModelAPI != nil {
+		api := *o.VLMPipelineModelAPI
+		if api.Concurrency == 0 {
+			api.Concurrency = 1
+		}
+		if api.Prompt == "" {
+			api.Prompt = defaultVLMPrompt
+		}
+		if api.Scale == 0 {
+			api.Scale = 2.0
+		}
+		if api.Timeout == 0 {
+			api.Timeout = 60
+		}
+		o.VLMPipelineModelAPI = &api
+	}
+	return o
+}
+
+// Canonical returns a stable encoding of the options: defaults are applied
+// and the format lists, whose order has no meaning, are sorted and deduplicated.
+// Two options producing the same conversion have the same canonical form.
+func (o ConvertOptions) Canonical() ([]byte, error) {
+	o = o.WithDefaults()
+	o.FromFormats = sortedUnique(o.FromFormats)
+	o.ToFormats = sortedUnique(o.ToFormats)
+	// encoding/json writes struct fields in declaration order and map keys
+	// sorted, which is all we need for a stable output
+	return json.Marshal(o)
+}
+
+// Hash returns the hex encoded SHA-256 of the canonical form of the options.
+func (o ConvertOptions) Hash() (string, error) {
+	data, err := o.Canonical()
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func sortedUnique[S ~[]E, E ~string](s S) S {
+	s = slices.Clone(s)
+	slices.Sort(s)
+	return slices.Compact(s)
+}
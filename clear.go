@@ -0,0 +1,44 @@
+package docling
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ClearConverters clears the converters cached by the server and frees their
+// memory.
+func (c *Client) ClearConverters(ctx context.Context) (ClearResponse, error) {
+	r, err := c.NewRequest(ctx, http.MethodGet, "clear/converters", nil)
+	if err != nil {
+		return ClearResponse{}, err
+	}
+	var resp ClearResponse
+	err = c.Do(r, &resp)
+	if err != nil {
+		return ClearResponse{}, err
+	}
+	return resp, nil
+}
+
+// ClearResults removes the results of the async tasks older than olderThan
+// from the server.
+func (c *Client) ClearResults(ctx context.Context, olderThan time.Duration) (ClearResponse, error) {
+	r, err := c.NewRequest(ctx, http.MethodGet, "clear/results", nil)
+	if err != nil {
+		return ClearResponse{}, err
+	}
+	// the parameter is misspelled in the API
+	r.URL.RawQuery = "older_then=" + strconv.FormatFloat(olderThan.Seconds(), 'f', -1, 64)
+	var resp ClearResponse
+	err = c.Do(r, &resp)
+	if err != nil {
+		return ClearResponse{}, err
+	}
+	return resp, nil
+}
+
+type ClearResponse struct {
+	Status string `json:"status"`
+}
@@ -1,7 +1,7 @@
 package docling
 
 import (
-	"bytes"
+	"bufio"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -9,6 +9,7 @@ import (
 	"log/slog"
 	"net/http"
 	"net/url"
+	"sync"
 )
 
 type ClientConfig struct {
@@ -30,6 +31,22 @@ func WithLogger(logger *slog.Logger) ClientOption {
 	}
 }
 
+// WithMaxInFlight limits the number of synchronous conversions running at the
+// same time. Callers above the limit wait in a FIFO queue.
+func WithMaxInFlight(n int) ClientOption {
+	return func(c *Client) {
+		c.syncLimiter = newLimiter(n)
+	}
+}
+
+// WithMaxAsyncInFlight limits the number of async task submissions running at
+// the same time. Callers above the limit wait in a FIFO queue.
+func WithMaxAsyncInFlight(n int) ClientOption {
+	return func(c *Client) {
+		c.asyncLimiter = newLimiter(n)
+	}
+}
+
 func NewClient(cfg ClientConfig, opts ...ClientOption) (*Client, error) {
 	u, err := url.Parse(cfg.BaseURL)
 	if err != nil {
@@ -48,26 +65,35 @@ func NewClient(cfg ClientConfig, opts ...ClientOption) (*Client, error) {
 }
 
 type Client struct {
-	apiKey  string
-	baseURL *url.URL
-	httpCli *http.Client
-	logger  *slog.Logger
+	apiKey          string
+	baseURL         *url.URL
+	httpCli         *http.Client
+	logger          *slog.Logger
+	syncLimiter     *limiter
+	asyncLimiter    *limiter
+	cache           Cache
+	skipValidation  bool
+	maxUploadSize   int64
+	detectFormats   bool
+	maxResponseSize int64
+	spool           *spoolConfig
 }
 
+// NewRequest creates a request to the API. The JSON encoding of in is streamed
+// to the connection as the request is sent, it is never fully held in memory.
 func (c *Client) NewRequest(ctx context.Context, method, path string, in any) (*http.Request, error) {
-	var b io.Reader
+	var b io.ReadCloser
 	if in != nil {
-		data, err := json.Marshal(in)
-		if err != nil {
-			return nil, fmt.Errorf("failed to json marshal in: %w", err)
-		}
-		b = bytes.NewReader(data)
+		b = &jsonBody{in: in}
 	}
 	req, err := http.NewRequestWithContext(ctx, method, c.apiURL(path), b)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create http request: %w", err)
 	}
 	if b != nil {
+		req.GetBody = func() (io.ReadCloser, error) {
+			return &jsonBody{in: in}, nil
+		}
 		req.Header.Set("Content-Type", "application/json")
 	}
 	if len(c.apiKey) > 0 {
@@ -76,37 +102,115 @@ func (c *Client) NewRequest(ctx context.Context, method, path string, in any) (*
 	return req, nil
 }
 
+// jsonBody encodes its value through a pipe, the encoding starts on the first
+// read so that a request which is never sent does not leak a goroutine.
+type jsonBody struct {
+	in   any
+	once sync.Once
+	pr   *io.PipeReader
+}
+
+func (b *jsonBody) start() {
+	pr, pw := io.Pipe()
+	b.pr = pr
+	go func() {
+		// the pipe hands writes over one by one, buffer the many small writes
+		// of the encoders
+		bw := bufio.NewWriterSize(pw, 32<<10)
+		var err error
+		if jw, ok := b.in.(jsonWriter); ok {
+			err = jw.writeJSON(bw)
+		} else {
+			err = json.NewEncoder(bw).Encode(b.in)
+		}
+		if err == nil {
+			err = bw.Flush()
+		}
+		if err != nil {
+			err = fmt.Errorf("failed to json encode in: %w", err)
+		}
+		pw.CloseWithError(err)
+	}()
+}
+
+func (b *jsonBody) Read(p []byte) (int, error) {
+	b.once.Do(b.start)
+	if b.pr == nil {
+		return 0, io.ErrClosedPipe
+	}
+	return b.pr.Read(p)
+}
+
+func (b *jsonBody) Close() error {
+	b.once.Do(func() {})
+	if b.pr == nil {
+		return nil
+	}
+	return b.pr.Close()
+}
+
 func (c *Client) Do(req *http.Request, out any) error {
 	resp, err := c.httpCli.Do(req)
 	if err != nil {
 		return fmt.Errorf("failed to do request: %w", err)
 	}
 	defer resp.Body.Close()
-	data, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("failed to read response body: %w", err)
+	var body io.Reader = resp.Body
+	var lr *limitedReader
+	if c.maxResponseSize > 0 {
+		lr = &limitedReader{r: body, limit: c.maxResponseSize}
+		body = lr
 	}
 	if resp.StatusCode != http.StatusOK {
-		return HTTPError{
+		data, err := io.ReadAll(body)
+		httpErr := HTTPError{
 			StatusCode: resp.StatusCode,
 			Body:       data,
 		}
-	}
-	if out != nil {
-		err = json.Unmarshal(data, out)
 		if err != nil {
-			return fmt.Errorf("failed to unmarshal response body: %w", err)
+			// keep the status code, with the part of the body read
+			if lr != nil {
+				httpErr.Body = data[:min(int64(len(data)), lr.limit)]
+			}
+			httpErr.Truncated = true
 		}
+		return httpErr
+	}
+	if out == nil {
+		return nil
+	}
+	if sd, ok := out.(streamDecoder); ok && c.spool != nil {
+		err = sd.decodeStream(body, c.spool)
+	} else {
+		err = json.NewDecoder(body).Decode(out)
+	}
+	if err == nil && lr != nil && lr.n > lr.limit {
+		// the decoder may have found a complete value in the bytes read
+		// along with the error
+		err = lr.err()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to decode response body: %w", err)
 	}
 	return nil
 }
 
+// streamDecoder is implemented by responses that can be decoded while they
+// are read, large fields being spooled out of memory.
+type streamDecoder interface {
+	decodeStream(r io.Reader, cfg *spoolConfig) error
+}
+
 type HTTPError struct {
 	StatusCode int
 	Body       []byte
+	Truncated  bool // the body could not be read whole, such as above WithMaxResponseSize
 }
 
 func (e HTTPError) Error() string {
+	if e.Truncated {
+		return fmt.Sprintf("http error: unexpected status code: %d, truncated body: %s", e.StatusCode, string(e.Body))
+	}
 	return fmt.Sprintf("http error: unexpected status code: %d, body: %s", e.StatusCode, string(e.Body))
 }
 
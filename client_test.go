@@ -0,0 +1,70 @@
+package docling
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"runtime"
+	"testing"
+)
+
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	clear(p)
+	return len(p), nil
+}
+
+func newSourceRequest(b testing.TB, size int64) *http.Request {
+	c, err := NewClient(ClientConfig{BaseURL: "http://127.0.0.1:5001"})
+	if err != nil {
+		b.Fatal(err)
+	}
+	r, err := c.NewRequest(context.Background(), http.MethodPost, "convert/source", ProcessURLRequest{
+		Sources: []Source{
+			SourceFromReader("a.pdf", io.LimitReader(zeroReader{}, size)),
+			SourceFromReader("b.pdf", io.LimitReader(zeroReader{}, size)),
+		},
+		Target: TargetInBody{},
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+	return r
+}
+
+func TestNewRequestBoundedMemory(t *testing.T) {
+	const size = 64 << 20
+	r := newSourceRequest(t, size)
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	n, err := io.Copy(io.Discard, r.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	runtime.ReadMemStats(&after)
+	if n < 2*size*4/3 {
+		t.Fatalf("expected at least %d bytes of body, got %d", 2*size*4/3, n)
+	}
+	// encoding the sources in memory would take at least their base64 size
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 1<<20 {
+		t.Fatalf("encoding the request allocated %d bytes", allocated)
+	}
+}
+
+func BenchmarkNewRequest(b *testing.B) {
+	for _, size := range []int64{1 << 20, 16 << 20, 64 << 20} {
+		b.Run(fmt.Sprintf("%dMB", size>>20), func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(2 * size)
+			for b.Loop() {
+				r := newSourceRequest(b, size)
+				_, err := io.Copy(io.Discard, r.Body)
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -1,7 +1,9 @@
 package docling
 
 import (
+	"bufio"
 	"bytes"
+	"cmp"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -10,6 +12,9 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 )
 
 func (c *Client) ProcessFileWithOptions(ctx context.Context, files []File, targetType TargetType, opts ...ConvertOption) (ConvertResponse, error) {
@@ -24,12 +29,13 @@ func (c *Client) ProcessFileWithOptions(ctx context.Context, files []File, targe
 	})
 }
 
-func writeFormFile(w *multipart.Writer, f File) error {
-	ff, err := w.CreateFormFile("files", filepath.Base(f.Name()))
+func writeFormFile(w *multipart.Writer, f File, uc *uploadCounter) error {
+	br := bufio.NewReader(uc.reader(f))
+	ff, err := w.CreatePart(formFileHeader("files", filepath.Base(f.Name()), sniffContentType(f.Name(), br)))
 	if err != nil {
 		return err
 	}
-	_, err = io.Copy(ff, f)
+	_, err = io.Copy(ff, br)
 	if err != nil {
 		return err
 	}
@@ -37,7 +43,35 @@ func writeFormFile(w *multipart.Writer, f File) error {
 }
 
 func (c *Client) ProcessFile(ctx context.Context, req ProcessFileRequest) (ConvertResponse, error) {
-	body, contentType := c.processFileBody(req)
+	err := c.validate(req.ConvertOptions)
+	if err != nil {
+		return ConvertResponse{}, err
+	}
+	req, err = c.detectFileFormats(req)
+	if err != nil {
+		return ConvertResponse{}, err
+	}
+	var cacheKey string
+	if c.cache != nil {
+		var cleanup func()
+		cacheKey, req, cleanup, err = fileCacheKey(req)
+		defer cleanup()
+		if err != nil {
+			return ConvertResponse{}, err
+		}
+		if resp, ok := c.cacheGet(ctx, cacheKey); ok {
+			return resp, nil
+		}
+	}
+	wait, err := c.syncLimiter.acquire(ctx)
+	if err != nil {
+		return ConvertResponse{}, err
+	}
+	defer c.syncLimiter.release()
+	body, contentType, err := c.processFileBody(req)
+	if err != nil {
+		return ConvertResponse{}, err
+	}
 	r, err := http.NewRequestWithContext(ctx, http.MethodPost, c.apiURL("convert/file"), body)
 	if err != nil {
 		return ConvertResponse{}, err
@@ -48,6 +82,11 @@ func (c *Client) ProcessFile(ctx context.Context, req ProcessFileRequest) (Conve
 	if err != nil {
 		return ConvertResponse{}, err
 	}
+	resp.Document.splitPageHTML(req.ToFormats)
+	if c.cache != nil {
+		c.cacheSet(ctx, cacheKey, resp)
+	}
+	resp.QueueWait = wait
 	return resp, nil
 }
 
@@ -64,7 +103,23 @@ func (c *Client) ProcessFileAsyncWithOptions(ctx context.Context, files []File,
 }
 
 func (c *Client) ProcessFileAsync(ctx context.Context, req ProcessFileRequest) (AsyncResponse, error) {
-	body, contentType := c.processFileBody(req)
+	err := c.validate(req.ConvertOptions)
+	if err != nil {
+		return AsyncResponse{}, err
+	}
+	req, err = c.detectFileFormats(req)
+	if err != nil {
+		return AsyncResponse{}, err
+	}
+	wait, err := c.asyncLimiter.acquire(ctx)
+	if err != nil {
+		return AsyncResponse{}, err
+	}
+	defer c.asyncLimiter.release()
+	body, contentType, err := c.processFileBody(req)
+	if err != nil {
+		return AsyncResponse{}, err
+	}
 	r, err := http.NewRequestWithContext(ctx, http.MethodPost, c.apiURL("convert/file/async"), body)
 	if err != nil {
 		return AsyncResponse{}, err
@@ -78,12 +133,14 @@ func (c *Client) ProcessFileAsync(ctx context.Context, req ProcessFileRequest) (
 	if err != nil {
 		return AsyncResponse{}, err
 	}
+	resp.QueueWait = wait
 	return resp, nil
 }
 
 type ProcessFileRequest struct {
 	Files      []File
 	TargetType TargetType
+	Progress   ProgressFunc // optional: called as the files are uploaded
 	ConvertOptions
 }
 
@@ -100,6 +157,31 @@ func (c *Client) ProcessURLWithOptions(ctx context.Context, srcs []Source, targe
 }
 
 func (c *Client) ProcessURL(ctx context.Context, req ProcessURLRequest) (ConvertResponse, error) {
+	err := c.validate(req.Options)
+	if err != nil {
+		return ConvertResponse{}, err
+	}
+	req, err = c.detectSourceFormats(req)
+	if err != nil {
+		return ConvertResponse{}, err
+	}
+	var cacheKey string
+	if c.cache != nil {
+		cacheKey, err = urlCacheKey(req)
+		if err != nil {
+			return ConvertResponse{}, err
+		}
+	}
+	if cacheKey != "" {
+		if resp, ok := c.cacheGet(ctx, cacheKey); ok {
+			return resp, nil
+		}
+	}
+	wait, err := c.syncLimiter.acquire(ctx)
+	if err != nil {
+		return ConvertResponse{}, err
+	}
+	defer c.syncLimiter.release()
 	r, err := c.NewRequest(ctx, http.MethodPost, "convert/source", req)
 	if err != nil {
 		return ConvertResponse{}, err
@@ -109,6 +191,11 @@ func (c *Client) ProcessURL(ctx context.Context, req ProcessURLRequest) (Convert
 	if err != nil {
 		return ConvertResponse{}, err
 	}
+	resp.Document.splitPageHTML(req.Options.ToFormats)
+	if cacheKey != "" {
+		c.cacheSet(ctx, cacheKey, resp)
+	}
+	resp.QueueWait = wait
 	return resp, nil
 }
 
@@ -125,6 +212,19 @@ func (c *Client) ProcessURLAsyncWithOptions(ctx context.Context, srcs []Source,
 }
 
 func (c *Client) ProcessURLAsync(ctx context.Context, req ProcessURLRequest) (AsyncResponse, error) {
+	err := c.validate(req.Options)
+	if err != nil {
+		return AsyncResponse{}, err
+	}
+	req, err = c.detectSourceFormats(req)
+	if err != nil {
+		return AsyncResponse{}, err
+	}
+	wait, err := c.asyncLimiter.acquire(ctx)
+	if err != nil {
+		return AsyncResponse{}, err
+	}
+	defer c.asyncLimiter.release()
 	r, err := c.NewRequest(ctx, http.MethodPost, "convert/source/async", req)
 	if err != nil {
 		return AsyncResponse{}, err
@@ -134,10 +234,20 @@ func (c *Client) ProcessURLAsync(ctx context.Context, req ProcessURLRequest) (As
 	if err != nil {
 		return AsyncResponse{}, err
 	}
+	resp.QueueWait = wait
 	return resp, nil
 }
 
-func (c *Client) processFileBody(req ProcessFileRequest) (io.Reader, string) {
+func (c *Client) processFileBody(req ProcessFileRequest) (io.Reader, string, error) {
+	total, err := c.checkUploadSize(req.Files)
+	if err != nil {
+		return nil, "", err
+	}
+	uc := &uploadCounter{
+		total:    total,
+		limit:    c.maxUploadSize,
+		progress: req.Progress,
+	}
 	pr, pw := io.Pipe()
 	w := multipart.NewWriter(pw)
 	go func() {
@@ -150,7 +260,7 @@ func (c *Client) processFileBody(req ProcessFileRequest) (io.Reader, string) {
 			return
 		}
 		for _, f := range req.Files {
-			err = writeFormFile(w, f)
+			err = writeFormFile(w, f, uc)
 			if err != nil {
 				return
 			}
@@ -170,7 +280,7 @@ func (c *Client) processFileBody(req ProcessFileRequest) (io.Reader, string) {
 			return
 		}
 	}()
-	return pr, w.FormDataContentType()
+	return pr, w.FormDataContentType(), nil
 }
 
 type ProcessURLRequest struct {
@@ -194,6 +304,8 @@ type ConvertResponse struct {
 		Times           []float64 `json:"times"`
 		StartTimestamps []string  `json:"start_timestamps"`
 	} `json:"timings"`
+	QueueWait time.Duration `json:"-"` // time spent waiting for a client-side slot
+	CacheHit  bool          `json:"-"` // true when the response was served from the client cache
 }
 
 type AsyncResponse struct {
@@ -207,6 +319,7 @@ type AsyncResponse struct {
 		NumSucceeded int `json:"num_succeeded"`
 		NumFailed    int `json:"num_failed"`
 	} `json:"task_meta"`
+	QueueWait time.Duration `json:"-"` // time spent waiting for a client-side slot
 }
 
 type Source interface {
@@ -222,8 +335,9 @@ const (
 )
 
 type SourceFile struct {
-	Base64String string `json:"base64_string"` // required
+	Base64String string `json:"base64_string"` // required unless built with a SourceFrom constructor
 	Filename     string `json:"filename"`      // required
+	content      *sourceContent
 }
 
 func (s SourceFile) Kind() SourceKind {
@@ -231,6 +345,14 @@ func (s SourceFile) Kind() SourceKind {
 }
 
 func (s SourceFile) MarshalJSON() ([]byte, error) {
+	if s.content != nil {
+		var b bytes.Buffer
+		err := s.writeJSON(&b)
+		if err != nil {
+			return nil, err
+		}
+		return b.Bytes(), nil
+	}
 	type Alias SourceFile
 	return json.Marshal(struct {
 		Kind SourceKind `json:"kind"`
@@ -387,70 +509,292 @@ type Document struct {
 	Contents []Content
 }
 
-func (d Document) MarkdownContent() string {
+// Content returns the content of the document in the given format. The string
+// accessors such as MarkdownContent return an empty string when a spooled
+// content cannot be read, use SpooledContent.Bytes to get the error.
+//
+// The server sends split page HTML in html_content. The responses of
+// ProcessFile, ProcessURL and ProcessFileSharded hold it as ToHTMLSplitPage
+// when it was requested without ToHTML. Results fetched by task ID, such as
+// with GetConvertTaskResult, are not tied to their request and hold it as
+// ToHTML, read it with HTMLContent.
+func (d Document) Content(format ToFormat) (Content, bool) {
 	for _, content := range d.Contents {
-		if content.Format() == ToMarkdown {
-			return content.String()
+		if content.Format() == format {
+			return content, true
 		}
 	}
-	return ""
+	return nil, false
+}
+
+func (d Document) contentString(format ToFormat) string {
+	content, ok := d.Content(format)
+	if !ok {
+		return ""
+	}
+	return content.String()
+}
+
+func (d Document) MarkdownContent() string {
+	return d.contentString(ToMarkdown)
 }
 
+func (d Document) HTMLContent() string {
+	return d.contentString(ToHTML)
+}
+
+// Deprecated: use HTMLContent.
 func (d Document) HTLMContent() string {
-	for _, content := range d.Contents {
-		if content.Format() == ToHTML {
-			return content.String()
+	return d.HTMLContent()
+}
+
+func (d Document) JSONContent() string {
+	return d.contentString(ToJSON)
+}
+
+func (d Document) DocTagsContent() string {
+	return d.contentString(ToDocTags)
+}
+
+func (d Document) TextContent() string {
+	return d.contentString(ToText)
+}
+
+// HTMLSplitPageContent returns the HTML export with one section per page, see
+// ToHTMLSplitPage.
+func (d Document) HTMLSplitPageContent() string {
+	return d.contentString(ToHTMLSplitPage)
+}
+
+// splitPageHTML marks the html content as split page HTML when that format was
+// requested instead of HTML, since the server sends both in html_content.
+func (d *Document) splitPageHTML(formats []ToFormat) {
+	if !slices.Contains(formats, ToHTMLSplitPage) || slices.Contains(formats, ToHTML) {
+		return
+	}
+	for i, content := range d.Contents {
+		switch content := content.(type) {
+		case HTMLContent:
+			d.Contents[i] = HTMLSplitPageContent(content)
+		case *SpooledContent:
+			if content.format == ToHTML {
+				content.format = ToHTMLSplitPage
+			}
 		}
 	}
-	return ""
 }
 
-func (d Document) JSONContent() string {
-	for _, content := range d.Contents {
-		if content.Format() == ToJSON {
-			return content.String()
+// wireContentFormats are the contents always present in the responses of the
+// server, null when they were not requested. Decoded documents list their
+// contents in this order, followed by the other formats sorted by name.
+var wireContentFormats = []ToFormat{ToMarkdown, ToJSON, ToHTML, ToDocTags, ToText}
+
+// sortContents sorts the contents of a decoded document, so the order does not
+// depend on the fields order of the response.
+func sortContents(contents []Content) {
+	rank := func(format ToFormat) int {
+		if i := slices.Index(wireContentFormats, format); i >= 0 {
+			return i
 		}
+		return len(wireContentFormats)
 	}
-	return ""
+	slices.SortStableFunc(contents, func(a, b Content) int {
+		return cmp.Or(cmp.Compare(rank(a.Format()), rank(b.Format())), strings.Compare(string(a.Format()), string(b.Format())))
+	})
 }
 
-func (d Document) DocTagsContent() string {
+// contentField returns the name of the response field of a format.
+func contentField(format ToFormat) string {
+	if format == ToMarkdown {
+		return "md_content"
+	}
+	return string(format) + "_content"
+}
+
+// MarshalJSON encodes the document in the shape of the server responses.
+func (d Document) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	filename, err := json.Marshal(d.Filename)
+	if err != nil {
+		return nil, err
+	}
+	b.WriteString(`{"filename":`)
+	b.Write(filename)
+	formats := slices.Clone(wireContentFormats)
 	for _, content := range d.Contents {
-		if content.Format() == ToDocTags {
-			return content.String()
+		if !slices.Contains(formats, content.Format()) {
+			formats = append(formats, content.Format())
+		}
+	}
+	slices.Sort(formats[len(wireContentFormats):])
+	for _, format := range formats {
+		b.WriteString(",")
+		key, err := json.Marshal(contentField(format))
+		if err != nil {
+			return nil, err
+		}
+		b.Write(key)
+		b.WriteString(":")
+		content, ok := d.Content(format)
+		if !ok {
+			b.WriteString("null")
+			continue
 		}
+		data, err := contentToJSON(content)
+		if err != nil {
+			return nil, err
+		}
+		b.Write(data)
+	}
+	b.WriteString("}")
+	return b.Bytes(), nil
+}
+
+// contentExts are the file extensions of the formats, others are written with
+// their format as extension.
+var contentExts = map[ToFormat]string{
+	ToMarkdown:      ".md",
+	ToJSON:          ".json",
+	ToHTML:          ".html",
+	ToHTMLSplitPage: ".split_page.html",
+	ToText:          ".txt",
+	ToDocTags:       ".doctags.txt",
+}
+
+// contentExt returns the file extension of a format, formats which are not
+// safe in file names are rejected.
+func contentExt(format ToFormat) (string, error) {
+	if ext, ok := contentExts[format]; ok {
+		return ext, nil
 	}
-	return ""
+	if !isFormatName(string(format)) {
+		return "", fmt.Errorf("invalid content format %q", format)
+	}
+	return "." + string(format), nil
 }
 
-func (d *Document) UnmarshalJSON(data []byte) error {
-	aux := &struct {
-		Filename       string           `json:"filename"`
-		MDContent      string           `json:"md_content"`
-		JSONContent    *json.RawMessage `json:"json_content"` // we need a pointer to json.RawMessage since the json.RawMessage is not nil if the parameter is set to null
-		HTMLContent    string           `json:"html_content"`
-		DocTagsContent string           `json:"doctags_content"`
-	}{}
-	err := json.Unmarshal(data, &aux)
+// WriteDir writes each content of the document to dir, in a file named after
+// the document with the extension of the format. Nothing is written when a
+// format is not safe in file names, but the files written before an I/O error
+// are left in place.
+func (d Document) WriteDir(dir string) error {
+	exts := make([]string, len(d.Contents))
+	for i, content := range d.Contents {
+		var err error
+		exts[i], err = contentExt(content.Format())
+		if err != nil {
+			return err
+		}
+	}
+	err := os.MkdirAll(dir, 0o755)
 	if err != nil {
 		return err
 	}
-	d.Filename = aux.Filename
-	if aux.MDContent != "" {
-		d.Contents = append(d.Contents, MarkdownContent(aux.MDContent))
+	stem := strings.TrimSuffix(filepath.Base(d.Filename), filepath.Ext(d.Filename))
+	if stem == "" || stem == "." || stem == string(filepath.Separator) {
+		stem = "document"
+	}
+	for i, content := range d.Contents {
+		err = writeContentFile(filepath.Join(dir, stem+exts[i]), content)
+		if err != nil {
+			return fmt.Errorf("failed to write %s content: %w", content.Format(), err)
+		}
+	}
+	return nil
+}
+
+func writeContentFile(name string, content Content) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return err
 	}
-	if aux.JSONContent != nil {
-		d.Contents = append(d.Contents, JSONContent(*aux.JSONContent))
+	if sc, ok := content.(*SpooledContent); ok {
+		var r io.ReadCloser
+		r, err = sc.Open()
+		if err == nil {
+			_, err = io.Copy(f, r)
+			r.Close()
+		}
+	} else {
+		_, err = io.WriteString(f, content.String())
 	}
-	if aux.HTMLContent != "" {
-		d.Contents = append(d.Contents, HTMLContent(aux.HTMLContent))
+	if cerr := f.Close(); err == nil {
+		err = cerr
 	}
-	if aux.DocTagsContent != "" {
-		d.Contents = append(d.Contents, DocTagsContent(aux.DocTagsContent))
+	return err
+}
+
+func (d *Document) UnmarshalJSON(data []byte) error {
+	var fields map[string]json.RawMessage
+	err := json.Unmarshal(data, &fields)
+	if err != nil {
+		return err
 	}
+	if raw, ok := fields["filename"]; ok {
+		err = json.Unmarshal(raw, &d.Filename)
+		if err != nil {
+			return err
+		}
+	}
+	for key, raw := range fields {
+		format, ok := contentFormat(key)
+		if !ok {
+			continue
+		}
+		content, err := contentFromJSON(format, raw)
+		if err != nil {
+			return fmt.Errorf("failed to decode %s: %w", key, err)
+		}
+		if content != nil {
+			d.Contents = append(d.Contents, content)
+		}
+	}
+	sortContents(d.Contents)
 	return nil
 }
 
+// contentFromJSON decodes the value of a content field, it returns nil for
+// null and empty values.
+func contentFromJSON(format ToFormat, raw json.RawMessage) (Content, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+	if format == ToJSON {
+		return JSONContent(raw), nil
+	}
+	if raw[0] != '"' {
+		return RawContent{ContentFormat: format, Data: string(raw), JSON: true}, nil
+	}
+	var s string
+	err := json.Unmarshal(raw, &s)
+	if err != nil {
+		return nil, err
+	}
+	if s == "" {
+		return nil, nil
+	}
+	return newContent(format, []byte(s)), nil
+}
+
+// contentToJSON encodes a content as the value of its field.
+func contentToJSON(content Content) (json.RawMessage, error) {
+	if rc, ok := content.(RawContent); ok && rc.JSON {
+		return json.RawMessage(rc.Data), nil
+	}
+	s, err := readContent(content)
+	if err != nil {
+		return nil, err
+	}
+	if content.Format() == ToJSON {
+		if strings.TrimSpace(s) == "" {
+			return json.RawMessage("null"), nil
+		}
+		return json.RawMessage(s), nil
+	}
+	return json.Marshal(s)
+}
+
 type Content interface {
 	Format() ToFormat
 	fmt.Stringer
@@ -496,6 +840,44 @@ func (c DocTagsContent) String() string {
 	return string(c)
 }
 
+type TextContent string
+
+func (c TextContent) Format() ToFormat {
+	return ToText
+}
+
+func (c TextContent) String() string {
+	return string(c)
+}
+
+// HTMLSplitPageContent is the HTML export with one section per page.
+type HTMLSplitPageContent string
+
+func (c HTMLSplitPageContent) Format() ToFormat {
+	return ToHTMLSplitPage
+}
+
+func (c HTMLSplitPageContent) String() string {
+	return string(c)
+}
+
+// RawContent is a content of a format unknown to this package, kept as sent by
+// the server: the text of a string value, or the JSON encoding of any other
+// value when JSON is set.
+type RawContent struct {
+	ContentFormat ToFormat
+	Data          string
+	JSON          bool
+}
+
+func (c RawContent) Format() ToFormat {
+	return c.ContentFormat
+}
+
+func (c RawContent) String() string {
+	return c.Data
+}
+
 type File interface {
 	Name() string
 	io.Reader
@@ -0,0 +1,141 @@
+package docling
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDocumentJSONRoundTrip(t *testing.T) {
+	doc := Document{
+		Filename: "report.pdf",
+		Contents: []Content{
+			MarkdownContent("# Title"),
+			JSONContent(`{"name":"report"}`),
+			HTMLSplitPageContent("<section></section>"),
+			RawContent{ContentFormat: "graph", Data: `{"nodes":[]}`, JSON: true},
+		},
+	}
+	data, err := json.Marshal(ConvertResponse{Status: "success", Document: doc})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields struct {
+		Document map[string]json.RawMessage `json:"document"`
+	}
+	err = json.Unmarshal(data, &fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key, want := range map[string]string{
+		"filename":                `"report.pdf"`,
+		"md_content":              `"# Title"`,
+		"json_content":            `{"name":"report"}`,
+		"html_content":            `null`,
+		"html_split_page_content": `"<section></section>"`,
+		"graph_content":           `{"nodes":[]}`,
+	} {
+		var got, expected any
+		json.Unmarshal(fields.Document[key], &got)
+		json.Unmarshal([]byte(want), &expected)
+		if !reflect.DeepEqual(got, expected) {
+			t.Fatalf("expected %s to be %s, got %s", key, want, fields.Document[key])
+		}
+	}
+	var resp ConvertResponse
+	err = json.Unmarshal(data, &resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, content := range doc.Contents {
+		got, ok := resp.Document.Content(content.Format())
+		if !ok || !reflect.DeepEqual(got, content) {
+			t.Fatalf("expected %#v, got %#v", content, got)
+		}
+	}
+	if len(resp.Document.Contents) != len(doc.Contents) {
+		t.Fatalf("expected %d contents, got %d", len(doc.Contents), len(resp.Document.Contents))
+	}
+}
+
+func TestDocumentWriteDir(t *testing.T) {
+	doc := Document{
+		Filename: "report.pdf",
+		Contents: []Content{MarkdownContent("# Title"), DocTagsContent("<doctag></doctag>"), RawContent{ContentFormat: "vtt", Data: "WEBVTT"}},
+	}
+	dir := t.TempDir()
+	err := doc.WriteDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string]string{
+		"report.md":          "# Title",
+		"report.doctags.txt": "<doctag></doctag>",
+		"report.vtt":         "WEBVTT",
+	} {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != want {
+			t.Fatalf("expected %s to hold %q, got %q", name, want, data)
+		}
+	}
+}
+
+func TestDocumentWriteDirInvalidFormat(t *testing.T) {
+	var doc Document
+	err := json.Unmarshal([]byte(`{"filename":"report.pdf","x/../../evil_content":"boom","vtt_content":"WEBVTT"}`), &doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Contents) != 1 || doc.Contents[0].Format() != "vtt" {
+		t.Fatalf("expected the invalid field to be ignored, got %+v", doc.Contents)
+	}
+	doc.Contents = append(doc.Contents, RawContent{ContentFormat: "../evil", Data: "boom"})
+	dir := filepath.Join(t.TempDir(), "out")
+	err = doc.WriteDir(dir)
+	if err == nil {
+		t.Fatal("expected an error for an invalid content format")
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Fatalf("expected nothing to be written, got %v", err)
+	}
+	matches, _ := filepath.Glob(filepath.Join(filepath.Dir(dir), "*evil*"))
+	if len(matches) != 0 {
+		t.Fatalf("expected nothing written outside of the directory, got %v", matches)
+	}
+}
+
+func TestProcessFileSplitPageHTML(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status":"success","document":{"filename":"doc.pdf","html_content":"<section>1</section>"}}`))
+	}))
+	defer srv.Close()
+	c, err := NewClient(ClientConfig{BaseURL: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, formats := range [][]ToFormat{{ToHTMLSplitPage}, {ToHTML, ToHTMLSplitPage}} {
+		resp, err := c.ProcessFile(context.Background(), ProcessFileRequest{
+			Files:          []File{FileReader{Filename: "doc.pdf", Reader: strings.NewReader("%PDF")}},
+			ConvertOptions: ConvertOptions{ToFormats: formats},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		split := len(formats) == 1
+		if _, ok := resp.Document.Content(ToHTMLSplitPage); ok != split {
+			t.Fatalf("expected split page content to be %t for %v, got %+v", split, formats, resp.Document.Contents)
+		}
+		if got := resp.Document.HTMLSplitPageContent() + resp.Document.HTMLContent(); got != "<section>1</section>" {
+			t.Fatalf("unexpected html %q", got)
+		}
+	}
+}
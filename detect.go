@@ -0,0 +1,341 @@
+package docling
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+var ErrUnsupportedFormat = errors.New("unsupported document format")
+
+const sniffLen = 8192
+
+func WithFormatDetection(enable bool) ClientOption {
+	return func(c *Client) {
+		c.detectFormats = enable
+	}
+}
+
+var extFormats = map[string]FromFormat{
+	".pdf":      FromPDF,
+	".docx":     FromDOCX,
+	".dotx":     FromDOCX,
+	".docm":     FromDOCX,
+	".pptx":     FromPPTX,
+	".potx":     FromPPTX,
+	".pptm":     FromPPTX,
+	".xlsx":     FromXLSX,
+	".xlsm":     FromXLSX,
+	".html":     FromHTML,
+	".htm":      FromHTML,
+	".xhtml":    FromHTML,
+	".md":       FromMarkdown,
+	".markdown": FromMarkdown,
+	".csv":      FromCSV,
+	".adoc":     FromASCIIDoc,
+	".asciidoc": FromASCIIDoc,
+	".asc":      FromASCIIDoc,
+	".nxml":     FromXMLJats,
+	".png":      FromImage,
+	".jpg":      FromImage,
+	".jpeg":     FromImage,
+	".tif":      FromImage,
+	".tiff":     FromImage,
+	".bmp":      FromImage,
+	".webp":     FromImage,
+	".wav":      FromAudio,
+	".mp3":      FromAudio,
+}
+
+// DetectFormat infers the docling input format of a document from its first
+// bytes (a few KB are enough), using its name when the content is ambiguous.
+func DetectFormat(name string, head []byte) (FromFormat, error) {
+	ext := strings.ToLower(filepath.Ext(name))
+	switch {
+	case bytes.HasPrefix(head, []byte("%PDF-")):
+		return FromPDF, nil
+	case bytes.HasPrefix(head, []byte("PK\x03\x04")):
+		// office documents are zip files, the local file headers of the
+		// first entries give away the kind of document
+		switch {
+		case bytes.Contains(head, []byte("word/")):
+			return FromDOCX, nil
+		case bytes.Contains(head, []byte("ppt/")):
+			return FromPPTX, nil
+		case bytes.Contains(head, []byte("xl/")):
+			return FromXLSX, nil
+		}
+		if f, ok := extFormats[ext]; ok && (f == FromDOCX || f == FromPPTX || f == FromXLSX) {
+			return f, nil
+		}
+		return "", fmt.Errorf("%w: %q is a zip archive but not an office document", ErrUnsupportedFormat, name)
+	case bytes.HasPrefix(head, []byte("\x89PNG\r\n\x1a\n")),
+		bytes.HasPrefix(head, []byte("\xff\xd8\xff")),
+		bytes.HasPrefix(head, []byte("II*\x00")),
+		bytes.HasPrefix(head, []byte("MM\x00*")),
+		bytes.HasPrefix(head, []byte("BM")) && ext == ".bmp",
+		isRIFF(head, "WEBP"):
+		return FromImage, nil
+	case bytes.HasPrefix(head, []byte("ID3")),
+		len(head) > 1 && head[0] == 0xff && head[1]&0xe0 == 0xe0 && ext == ".mp3",
+		isRIFF(head, "WAVE"):
+		return FromAudio, nil
+	case bytes.HasPrefix(head, []byte("\x1f\x8b")):
+		// METS GBS documents are tar.gz archives
+		if strings.HasSuffix(strings.ToLower(name), ".tar.gz") || ext == ".tgz" {
+			return FromMetsGbs, nil
+		}
+		return "", fmt.Errorf("%w: %q is a gzip archive", ErrUnsupportedFormat, name)
+	}
+	if !isText(head) {
+		return "", fmt.Errorf("%w: %q is a binary file of unknown type", ErrUnsupportedFormat, name)
+	}
+	text := bytes.TrimSpace(bytes.TrimPrefix(head, []byte("\xef\xbb\xbf")))
+	lower := bytes.ToLower(text)
+	switch {
+	case bytes.HasPrefix(text, []byte("{")):
+		if bytes.Contains(text, []byte(`"DoclingDocument"`)) {
+			return FromJSONDocling, nil
+		}
+		return "", fmt.Errorf("%w: %q is a JSON file but not a DoclingDocument", ErrUnsupportedFormat, name)
+	case bytes.HasPrefix(text, []byte("<")):
+		root := xmlRootElement(lower)
+		switch {
+		case root == "html", bytes.HasPrefix(lower, []byte("<!doctype html")):
+			return FromHTML, nil
+		case extFormats[ext] == FromHTML:
+			return FromHTML, nil
+		case strings.HasPrefix(root, "us-patent-"), root == "patdoc":
+			return FromXMLUspto, nil
+		case root == "article", isJATSDoctype(lower):
+			return FromXMLJats, nil
+		case root == "mets":
+			return FromMetsGbs, nil
+		case bytes.Contains(lower, []byte("<html")), bytes.Contains(lower, []byte("<body")):
+			return FromHTML, nil
+		}
+	}
+	if f, ok := extFormats[ext]; ok {
+		switch f {
+		case FromMarkdown, FromCSV, FromASCIIDoc, FromHTML:
+			return f, nil
+		}
+	}
+	// other text files, such as source code, may have lines starting with #
+	if (ext == "" || ext == ".txt") && markdownHeadingRegexp.Match(text) {
+		return FromMarkdown, nil
+	}
+	return "", fmt.Errorf("%w: cannot tell the format of text file %q", ErrUnsupportedFormat, name)
+}
+
+var markdownHeadingRegexp = regexp.MustCompile(`(?m)^#{1,6}[ \t]+\S`)
+
+// xmlRootElement returns the local name of the first element of a lowercased
+// XML or HTML document, skipping the declaration, comments and doctype.
+func xmlRootElement(doc []byte) string {
+	for {
+		doc = bytes.TrimSpace(doc)
+		switch {
+		case bytes.HasPrefix(doc, []byte("<?")):
+			end := bytes.Index(doc, []byte("?>"))
+			if end < 0 {
+				return ""
+			}
+			doc = doc[end+2:]
+		case bytes.HasPrefix(doc, []byte("<!--")):
+			end := bytes.Index(doc, []byte("-->"))
+			if end < 0 {
+				return ""
+			}
+			doc = doc[end+3:]
+		case bytes.HasPrefix(doc, []byte("<!")):
+			end := bytes.IndexByte(doc, '>')
+			if end < 0 {
+				return ""
+			}
+			doc = doc[end+1:]
+		case bytes.HasPrefix(doc, []byte("<")):
+			name := doc[1:]
+			if end := bytes.IndexAny(name, " \t\r\n/>"); end >= 0 {
+				name = name[:end]
+			}
+			if i := bytes.IndexByte(name, ':'); i >= 0 {
+				name = name[i+1:]
+			}
+			return string(name)
+		default:
+			return ""
+		}
+	}
+}
+
+// isJATSDoctype reports whether a lowercased document declares a JATS or NLM
+// DTD.
+func isJATSDoctype(doc []byte) bool {
+	start := bytes.Index(doc, []byte("<!doctype"))
+	if start < 0 {
+		return false
+	}
+	doctype := doc[start:]
+	if end := bytes.IndexByte(doctype, '>'); end >= 0 {
+		doctype = doctype[:end]
+	}
+	return bytes.Contains(doctype, []byte("jats")) || bytes.Contains(doctype, []byte("//nlm//dtd"))
+}
+
+func isRIFF(head []byte, kind string) bool {
+	return len(head) >= 12 && string(head[:4]) == "RIFF" && string(head[8:12]) == kind
+}
+
+func isText(head []byte) bool {
+	if bytes.IndexByte(head, 0) >= 0 {
+		return false
+	}
+	// the sniffed prefix may cut a multi-byte rune
+	for i := 0; i < utf8.UTFMax-1 && len(head) > 0 && !utf8.Valid(head); i++ {
+		head = head[:len(head)-1]
+	}
+	return utf8.Valid(head)
+}
+
+// DetectFileFormat detects the format of a file. Since the first bytes of the
+// file are consumed, the returned File must be used in place of f.
+func DetectFileFormat(f File) (FromFormat, File, error) {
+	br := bufio.NewReaderSize(f, sniffLen)
+	head, err := br.Peek(sniffLen)
+	if err != nil && len(head) == 0 {
+		return "", nil, fmt.Errorf("failed to read %q: %w", f.Name(), err)
+	}
+	pf := peekedFile{File: f, br: br}
+	format, err := DetectFormat(f.Name(), head)
+	if err != nil {
+		return "", pf, err
+	}
+	return format, pf, nil
+}
+
+// DetectSourceFormat detects the format of a file source.
+func DetectSourceFormat(s SourceFile) (FromFormat, error) {
+	head, err := s.head(sniffLen)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %q: %w", s.Filename, err)
+	}
+	return DetectFormat(s.Filename, head)
+}
+
+type peekedFile struct {
+	File
+	br *bufio.Reader
+}
+
+func (pf peekedFile) Read(p []byte) (int, error) {
+	return pf.br.Read(p)
+}
+
+// Size returns the size left to read, including the bytes buffered while
+// sniffing the format, or -1 when the size of the file is unknown.
+func (pf peekedFile) Size() int64 {
+	size := fileSize(pf.File)
+	if size < 0 {
+		return -1
+	}
+	return size + int64(pf.br.Buffered())
+}
+
+type detectedFormat struct {
+	name   string
+	format FromFormat
+}
+
+// checkFormats makes sure every detected format is one of the requested
+// formats. When no format was requested, the detected ones are returned.
+func checkFormats(formats []FromFormat, detected []detectedFormat) ([]FromFormat, error) {
+	var errs []error
+	var found []FromFormat
+	for _, d := range detected {
+		if len(formats) > 0 && !slices.Contains(formats, d.format) {
+			errs = append(errs, fmt.Errorf("%w: %q is %s which is not in the requested formats %v", ErrUnsupportedFormat, d.name, d.format, formats))
+		}
+		found = append(found, d.format)
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	if len(formats) > 0 {
+		return formats, nil
+	}
+	return sortedUnique(found), nil
+}
+
+func (c *Client) detectFileFormats(req ProcessFileRequest) (ProcessFileRequest, error) {
+	if !c.detectFormats {
+		return req, nil
+	}
+	var errs []error
+	detected := make([]detectedFormat, 0, len(req.Files))
+	files := make([]File, len(req.Files))
+	for i, f := range req.Files {
+		format, pf, err := DetectFileFormat(f)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		detected = append(detected, detectedFormat{name: f.Name(), format: format})
+		files[i] = pf
+	}
+	if len(errs) > 0 {
+		return req, errors.Join(errs...)
+	}
+	formats, err := checkFormats(req.FromFormats, detected)
+	if err != nil {
+		return req, err
+	}
+	req.Files = files
+	req.FromFormats = formats
+	return req, nil
+}
+
+func (c *Client) detectSourceFormats(req ProcessURLRequest) (ProcessURLRequest, error) {
+	if !c.detectFormats {
+		return req, nil
+	}
+	var (
+		errs   []error
+		remote bool
+	)
+	detected := make([]detectedFormat, 0, len(req.Sources))
+	for _, src := range req.Sources {
+		sf, ok := asSourceFile(src)
+		if !ok {
+			// the content of remote sources is not known before conversion
+			remote = true
+			continue
+		}
+		format, err := DetectSourceFormat(sf)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		detected = append(detected, detectedFormat{name: sf.Filename, format: format})
+	}
+	if len(errs) > 0 {
+		return req, errors.Join(errs...)
+	}
+	formats, err := checkFormats(req.Options.FromFormats, detected)
+	if err != nil {
+		return req, err
+	}
+	if remote && len(req.Options.FromFormats) == 0 {
+		// restricting the formats to the detected ones could reject the
+		// remote sources
+		return req, nil
+	}
+	req.Options.FromFormats = formats
+	return req, nil
+}
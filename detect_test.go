@@ -0,0 +1,107 @@
+package docling
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDetectFormat(t *testing.T) {
+	cases := []struct {
+		name     string
+		filename string
+		head     string
+		expected FromFormat
+	}{
+		{name: "PDF", filename: "doc", head: "%PDF-1.7\n", expected: FromPDF},
+		{name: "DOCX", filename: "doc.zip", head: "PK\x03\x04\x14\x00[Content_Types].xmlPK\x03\x04word/document.xml", expected: FromDOCX},
+		{name: "XLSX", filename: "sheet", head: "PK\x03\x04\x14\x00xl/workbook.xml", expected: FromXLSX},
+		{name: "PNG", filename: "scan", head: "\x89PNG\r\n\x1a\n\x00\x00", expected: FromImage},
+		{name: "WAV", filename: "talk", head: "RIFF\x24\x00\x00\x00WAVEfmt ", expected: FromAudio},
+		{name: "HTML", filename: "page", head: "<!DOCTYPE html><html><body></body></html>", expected: FromHTML},
+		{name: "JATS", filename: "paper.xml", head: `<?xml version="1.0"?><!DOCTYPE article PUBLIC "-//NLM//DTD JATS (Z39.96) Journal Archiving and Interchange DTD v1.2 20190208//EN" "JATS-archivearticle1.dtd"><article>`, expected: FromXMLJats},
+		{name: "USPTO", filename: "patent.xml", head: `<?xml version="1.0"?><us-patent-grant lang="EN">`, expected: FromXMLUspto},
+		{name: "DoclingJSON", filename: "doc.json", head: `{"schema_name": "DoclingDocument", "version": "1.3.0"}`, expected: FromJSONDocling},
+		{name: "CSV", filename: "data.csv", head: "a,b\n1,2\n", expected: FromCSV},
+		{name: "Markdown", filename: "notes", head: "# Title\n\nSome text", expected: FromMarkdown},
+		{name: "HTML5Article", filename: "page", head: "<!DOCTYPE html><html><body><article><p>news</p></article></body></html>", expected: FromHTML},
+		{name: "HTMLExtension", filename: "page.html", head: "<div><article>news</article></div>", expected: FromHTML},
+		{name: "JATSRoot", filename: "paper.xml", head: `<?xml version="1.0"?><!-- exported --><article xmlns:xlink="http://www.w3.org/1999/xlink">`, expected: FromXMLJats},
+		{name: "MarkdownText", filename: "notes.txt", head: "Intro\n\n## Section\n", expected: FromMarkdown},
+		{name: "CSource", filename: "main.c", head: "#include <stdio.h>\n\nint main() {}\n"},
+		{name: "Hashtags", filename: "notes", head: "Tags:\n#golang #docling\n"},
+		{name: "Binary", filename: "blob", head: "\x00\x01\x02\x03"},
+		{name: "Zip", filename: "archive.zip", head: "PK\x03\x04\x14\x00data.bin"},
+	}
+	for _, c := range cases {
+		got, err := DetectFormat(c.filename, []byte(c.head))
+		if c.expected == "" {
+			if !errors.Is(err, ErrUnsupportedFormat) {
+				t.Fatalf("%s: expected unsupported format error, got %q, %v", c.name, got, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		if got != c.expected {
+			t.Fatalf("%s: expected %s, got %s", c.name, c.expected, got)
+		}
+	}
+}
+
+func TestDetectSourceFormatsMixed(t *testing.T) {
+	c, err := NewClient(ClientConfig{BaseURL: "http://127.0.0.1"}, WithFormatDetection(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := ProcessURLRequest{Sources: []Source{
+		SourceHTTP{URL: "https://example.com/report.docx"},
+		SourceFromBytes("doc.pdf", []byte("%PDF-1.7\n")),
+	}}
+	got, err := c.detectSourceFormats(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Options.FromFormats) != 0 {
+		t.Fatalf("expected formats to be left unset with a remote source, got %v", got.Options.FromFormats)
+	}
+	req.Options.FromFormats = []FromFormat{FromDOCX}
+	_, err = c.detectSourceFormats(req)
+	if !errors.Is(err, ErrUnsupportedFormat) {
+		t.Fatalf("expected the file source to be checked, got %v", err)
+	}
+}
+
+func TestDetectFileFormatSize(t *testing.T) {
+	for _, size := range []int{100, 20009} {
+		path := filepath.Join(t.TempDir(), "doc.md")
+		err := os.WriteFile(path, append([]byte("# Title\n\n"), bytes.Repeat([]byte("a"), size-9)...), 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		_, pf, err := DetectFileFormat(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := fileSize(pf); got != int64(size) {
+			t.Fatalf("expected size %d, got %d", size, got)
+		}
+	}
+	_, pf, err := DetectFileFormat(FileReader{Filename: "doc.md", Reader: io.MultiReader(strings.NewReader("# Title"))})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := fileSize(pf); got != -1 {
+		t.Fatalf("expected an unknown size, got %d", got)
+	}
+}
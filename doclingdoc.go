@@ -0,0 +1,248 @@
+package docling
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+var ErrNoJSONContent = errors.New("document has no json content")
+
+// DoclingDocument is the typed form of the json_content of a conversion, the
+// fields not listed here are ignored.
+type DoclingDocument struct {
+	SchemaName    string            `json:"schema_name"`
+	Version       string            `json:"version"`
+	Name          string            `json:"name"`
+	Origin        *DocumentOrigin   `json:"origin,omitempty"`
+	Furniture     *GroupItem        `json:"furniture,omitempty"` // deprecated: replaced by content layers
+	Body          GroupItem         `json:"body"`
+	Groups        []GroupItem       `json:"groups"`
+	Texts         []TextItem        `json:"texts"`
+	Pictures      []PictureItem     `json:"pictures"`
+	Tables        []TableItem       `json:"tables"`
+	KeyValueItems []json.RawMessage `json:"key_value_items"`
+	FormItems     []json.RawMessage `json:"form_items"`
+	Pages         map[int]PageItem  `json:"pages"`
+}
+
+type DocumentOrigin struct {
+	Mimetype   string `json:"mimetype"`
+	BinaryHash uint64 `json:"binary_hash"`
+	Filename   string `json:"filename"`
+	URI        string `json:"uri,omitempty"`
+}
+
+// Ref is a JSON pointer to an item of the document, such as "#/texts/12".
+type Ref struct {
+	Ref string `json:"$ref"`
+}
+
+// item returns the array and the index addressed by the ref.
+func (r Ref) item() (string, int, bool) {
+	m := itemRefRegexp.FindStringSubmatch(r.Ref)
+	if m == nil {
+		return "", 0, false
+	}
+	i, err := strconv.Atoi(m[2])
+	if err != nil {
+		return "", 0, false
+	}
+	return m[1], i, true
+}
+
+type NodeItem struct {
+	SelfRef      string       `json:"self_ref"`
+	Parent       *Ref         `json:"parent,omitempty"`
+	Children     []Ref        `json:"children"`
+	ContentLayer ContentLayer `json:"content_layer,omitempty"` // default: "body"
+}
+
+type ContentLayer string
+
+const (
+	ContentLayerBody       ContentLayer = "body"
+	ContentLayerFurniture  ContentLayer = "furniture"
+	ContentLayerBackground ContentLayer = "background"
+	ContentLayerInvisible  ContentLayer = "invisible"
+	ContentLayerNotes      ContentLayer = "notes"
+)
+
+type GroupItem struct {
+	NodeItem
+	Name  string `json:"name"`
+	Label string `json:"label"` // "unspecified" "list" "ordered_list" "chapter" "section" "sheet" "slide" "form_area" "key_value_area" "comment_section" "inline" "picture_area"
+}
+
+type DocItem struct {
+	NodeItem
+	Label string           `json:"label"`
+	Prov  []ProvenanceItem `json:"prov"`
+}
+
+type TextItem struct {
+	DocItem
+	Orig       string `json:"orig"`
+	Text       string `json:"text"`
+	Level      int    `json:"level,omitempty"`      // section headers
+	Marker     string `json:"marker,omitempty"`     // list items
+	Enumerated bool   `json:"enumerated,omitempty"` // list items
+}
+
+type FloatingItem struct {
+	DocItem
+	Captions   []Ref     `json:"captions"`
+	References []Ref     `json:"references"`
+	Footnotes  []Ref     `json:"footnotes"`
+	Image      *ImageRef `json:"image,omitempty"`
+}
+
+type PictureItem struct {
+	FloatingItem
+	Annotations []PictureAnnotation `json:"annotations"`
+	Meta        *PictureMeta        `json:"meta,omitempty"` // replaces annotations in recent versions
+}
+
+// PictureAnnotation is a classification or a description of a picture, the
+// other kinds of annotations are only identified by their kind.
+type PictureAnnotation struct {
+	Kind             string         `json:"kind"` // "classification" "description" ...
+	Provenance       string         `json:"provenance,omitempty"`
+	PredictedClasses []PictureClass `json:"predicted_classes,omitempty"` // classification
+	Text             string         `json:"text,omitempty"`              // description
+}
+
+type PictureClass struct {
+	ClassName  string  `json:"class_name"`
+	Confidence float64 `json:"confidence"`
+}
+
+type PictureMeta struct {
+	Classification *struct {
+		Predictions []PictureClass `json:"predictions"`
+	} `json:"classification,omitempty"`
+	Description *struct {
+		Text string `json:"text"`
+	} `json:"description,omitempty"`
+}
+
+type TableItem struct {
+	FloatingItem
+	Data TableData `json:"data"`
+}
+
+type TableData struct {
+	TableCells []TableCell   `json:"table_cells"`
+	NumRows    int           `json:"num_rows"`
+	NumCols    int           `json:"num_cols"`
+	Grid       [][]TableCell `json:"grid,omitempty"`
+}
+
+type TableCell struct {
+	BBox         *BoundingBox `json:"bbox,omitempty"`
+	RowSpan      int          `json:"row_span"`
+	ColSpan      int          `json:"col_span"`
+	StartRow     int          `json:"start_row_offset_idx"`
+	EndRow       int          `json:"end_row_offset_idx"` // exclusive
+	StartCol     int          `json:"start_col_offset_idx"`
+	EndCol       int          `json:"end_col_offset_idx"` // exclusive
+	Text         string       `json:"text"`
+	ColumnHeader bool         `json:"column_header"`
+	RowHeader    bool         `json:"row_header"`
+	RowSection   bool         `json:"row_section"`
+}
+
+type BoundingBox struct {
+	L           float64     `json:"l"`
+	T           float64     `json:"t"`
+	R           float64     `json:"r"`
+	B           float64     `json:"b"`
+	CoordOrigin CoordOrigin `json:"coord_origin"`
+}
+
+type CoordOrigin string
+
+const (
+	CoordOriginTopLeft    CoordOrigin = "TOPLEFT"
+	CoordOriginBottomLeft CoordOrigin = "BOTTOMLEFT"
+)
+
+type ProvenanceItem struct {
+	PageNo   int         `json:"page_no"`
+	BBox     BoundingBox `json:"bbox"`
+	Charspan [2]int      `json:"charspan"`
+}
+
+type Size struct {
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+}
+
+type ImageRef struct {
+	Mimetype string `json:"mimetype"`
+	DPI      int    `json:"dpi"`
+	Size     Size   `json:"size"`
+	URI      string `json:"uri"` // data URI in embedded mode, path in referenced mode
+}
+
+type PageItem struct {
+	Size   Size      `json:"size"`
+	Image  *ImageRef `json:"image,omitempty"`
+	PageNo int       `json:"page_no"`
+}
+
+func ParseDoclingDocument(r io.Reader) (*DoclingDocument, error) {
+	var doc DoclingDocument
+	err := json.NewDecoder(r).Decode(&doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode docling document: %w", err)
+	}
+	return &doc, nil
+}
+
+// DoclingDocument decodes the json content of the document, which must have
+// been requested with ToJSON.
+func (d Document) DoclingDocument() (*DoclingDocument, error) {
+	for _, content := range d.Contents {
+		if content.Format() != ToJSON {
+			continue
+		}
+		if sc, ok := content.(*SpooledContent); ok {
+			f, err := sc.Open()
+			if err != nil {
+				return nil, err
+			}
+			defer f.Close()
+			return ParseDoclingDocument(f)
+		}
+		var doc DoclingDocument
+		err := json.Unmarshal([]byte(content.String()), &doc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode docling document: %w", err)
+		}
+		return &doc, nil
+	}
+	return nil, ErrNoJSONContent
+}
+
+// Text returns the text item the ref points to.
+func (doc *DoclingDocument) Text(ref Ref) (*TextItem, bool) {
+	array, i, ok := ref.item()
+	if !ok || array != "texts" || i >= len(doc.Texts) {
+		return nil, false
+	}
+	return &doc.Texts[i], true
+}
+
+// texts returns the text of the text items the refs point to.
+func (doc *DoclingDocument) texts(refs []Ref) []string {
+	var texts []string
+	for _, ref := range refs {
+		if t, ok := doc.Text(ref); ok && t.Text != "" {
+			texts = append(texts, t.Text)
+		}
+	}
+	return texts
+}
@@ -0,0 +1,163 @@
+package docling
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// itemArrays are the DoclingDocument arrays addressed by JSON pointer refs
+// such as "#/texts/12".
+var itemArrays = []string{"texts", "tables", "pictures", "groups", "key_value_items", "form_items"}
+
+var itemRefRegexp = regexp.MustCompile(`^#/(texts|tables|pictures|groups|key_value_items|form_items)/(\d+)$`)
+
+// mergeDoclingDocuments appends the items of DoclingDocuments converted from
+// consecutive page ranges of the same file. Refs of each document are shifted
+// past the items of the previous ones, and page numbers are moved to the
+// original document numbering when the server numbered the shard from 1.
+func mergeDoclingDocuments(docs []json.RawMessage, ranges []PageRange) (json.RawMessage, error) {
+	var merged map[string]any
+	for i, raw := range docs {
+		var doc map[string]any
+		d := json.NewDecoder(bytes.NewReader(raw))
+		d.UseNumber()
+		err := d.Decode(&doc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode document of pages %s: %w", ranges[i], err)
+		}
+		if merged == nil {
+			merged = doc
+			shiftPages(merged, pageOffset(merged, ranges[i]))
+			continue
+		}
+		offsets := make(map[string]int, len(itemArrays))
+		for _, name := range itemArrays {
+			items, _ := merged[name].([]any)
+			offsets[name] = len(items)
+		}
+		shiftRefs(doc, offsets)
+		shiftPages(doc, pageOffset(doc, ranges[i]))
+		for _, name := range itemArrays {
+			items, _ := doc[name].([]any)
+			if len(items) == 0 {
+				continue
+			}
+			existing, _ := merged[name].([]any)
+			merged[name] = append(existing, items...)
+		}
+		for _, name := range []string{"body", "furniture"} {
+			src, _ := doc[name].(map[string]any)
+			dst, _ := merged[name].(map[string]any)
+			if src == nil || dst == nil {
+				continue
+			}
+			srcChildren, _ := src["children"].([]any)
+			dstChildren, _ := dst["children"].([]any)
+			dst["children"] = append(dstChildren, srcChildren...)
+		}
+		pages, _ := doc["pages"].(map[string]any)
+		mergedPages, _ := merged["pages"].(map[string]any)
+		if mergedPages == nil {
+			mergedPages = make(map[string]any, len(pages))
+			merged["pages"] = mergedPages
+		}
+		for k, v := range pages {
+			mergedPages[k] = v
+		}
+	}
+	if merged == nil {
+		return nil, nil
+	}
+	return json.Marshal(merged)
+}
+
+func shiftRefs(v any, offsets map[string]int) {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, child := range v {
+			if s, ok := child.(string); ok && (k == "$ref" || k == "self_ref" || k == "cref") {
+				v[k] = shiftRef(s, offsets)
+				continue
+			}
+			shiftRefs(child, offsets)
+		}
+	case []any:
+		for _, child := range v {
+			shiftRefs(child, offsets)
+		}
+	}
+}
+
+func shiftRef(ref string, offsets map[string]int) string {
+	m := itemRefRegexp.FindStringSubmatch(ref)
+	if m == nil {
+		return ref
+	}
+	idx, err := strconv.Atoi(m[2])
+	if err != nil {
+		return ref
+	}
+	return fmt.Sprintf("#/%s/%d", m[1], idx+offsets[m[1]])
+}
+
+// pageOffset returns how much the page numbers of a shard document must be
+// shifted to match the numbering of the whole document.
+func pageOffset(doc map[string]any, r PageRange) int {
+	from, _ := r.bounds()
+	pages, _ := doc["pages"].(map[string]any)
+	first := 0
+	for k := range pages {
+		n, err := strconv.Atoi(k)
+		if err != nil {
+			continue
+		}
+		if first == 0 || n < first {
+			first = n
+		}
+	}
+	if first == 0 || first >= from {
+		return 0
+	}
+	return from - first
+}
+
+func shiftPages(doc map[string]any, offset int) {
+	if offset == 0 {
+		return
+	}
+	if pages, ok := doc["pages"].(map[string]any); ok {
+		shifted := make(map[string]any, len(pages))
+		for k, v := range pages {
+			n, err := strconv.Atoi(k)
+			if err != nil {
+				shifted[k] = v
+				continue
+			}
+			shifted[strconv.Itoa(n+offset)] = v
+		}
+		doc["pages"] = shifted
+	}
+	shiftPageNumbers(doc, offset)
+}
+
+func shiftPageNumbers(v any, offset int) {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, child := range v {
+			if n, ok := child.(json.Number); ok && k == "page_no" {
+				if i, err := n.Int64(); err == nil {
+					v[k] = json.Number(strconv.FormatInt(i+int64(offset), 10))
+				}
+				continue
+			}
+			shiftPageNumbers(child, offset)
+		}
+	case []any:
+		for _, child := range v {
+			shiftPageNumbers(child, offset)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package docling
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMergeDoclingDocuments(t *testing.T) {
+	docs := []json.RawMessage{
+		[]byte(`{"body":{"self_ref":"#/body","children":[{"$ref":"#/texts/0"},{"$ref":"#/tables/0"}]},"texts":[{"self_ref":"#/texts/0","parent":{"$ref":"#/body"},"prov":[{"page_no":1}]}],"tables":[{"self_ref":"#/tables/0","captions":[{"$ref":"#/texts/0"}],"prov":[{"page_no":2}]}],"pages":{"1":{"page_no":1},"2":{"page_no":2}}}`),
+		// the second shard was numbered from 1 by the server
+		[]byte(`{"body":{"self_ref":"#/body","children":[{"$ref":"#/texts/0"},{"$ref":"#/texts/1"}]},"texts":[{"self_ref":"#/texts/0","parent":{"$ref":"#/body"},"prov":[{"page_no":1}]},{"self_ref":"#/texts/1","parent":{"$ref":"#/body"},"prov":[{"page_no":2}]}],"pages":{"1":{"page_no":1},"2":{"page_no":2}}}`),
+	}
+	merged, err := mergeDoclingDocuments(docs, []PageRange{{From: 1, To: 2}, {From: 3, To: 4}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"body":{"children":[{"$ref":"#/texts/0"},{"$ref":"#/tables/0"},{"$ref":"#/texts/1"},{"$ref":"#/texts/2"}],"self_ref":"#/body"},"pages":{"1":{"page_no":1},"2":{"page_no":2},"3":{"page_no":3},"4":{"page_no":4}},"tables":[{"captions":[{"$ref":"#/texts/0"}],"prov":[{"page_no":2}],"self_ref":"#/tables/0"}],"texts":[{"parent":{"$ref":"#/body"},"prov":[{"page_no":1}],"self_ref":"#/texts/0"},{"parent":{"$ref":"#/body"},"prov":[{"page_no":3}],"self_ref":"#/texts/1"},{"parent":{"$ref":"#/body"},"prov":[{"page_no":4}],"self_ref":"#/texts/2"}]}`
+	if got := string(merged); got != expected {
+		t.Fatalf("\nexpected:\n%s\ngot:\n%s", expected, got)
+	}
+}
+
+func TestMergeConvertResponses(t *testing.T) {
+	resps := []ConvertResponse{
+		{Status: "success", Document: Document{Filename: "doc.pdf", Contents: []Content{
+			HTMLContent("<html><head><title>doc</title></head><body><p>one</p></body></html>"),
+			TextContent("one"),
+		}}},
+		{Status: "success", Document: Document{Filename: "doc.pdf", Contents: []Content{
+			HTMLContent("<html><head><title>doc</title></head><BODY class=\"x\"><p>two</p></BODY></html>"),
+			TextContent("two"),
+		}}},
+	}
+	merged, err := mergeConvertResponses(resps, []PageRange{{From: 1, To: 1}, {From: 2, To: 2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := merged.Document.HTMLContent(), "<html><head><title>doc</title></head><body><p>one</p>\n<p>two</p></body></html>"; got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+	if got := merged.Document.TextContent(); got != "one\n\ntwo" {
+		t.Fatalf("unexpected text %q", got)
+	}
+	resps[1].Document.Contents = append(resps[1].Document.Contents, RawContent{ContentFormat: "vtt", Data: "WEBVTT"})
+	_, err = mergeConvertResponses(resps, []PageRange{{From: 1, To: 1}, {From: 2, To: 2}})
+	if err == nil {
+		t.Fatal("expected an error for contents which cannot be merged")
+	}
+}
+
+func TestProcessFileShardedSpooling(t *testing.T) {
+	var fail atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/v1/convert/file/async":
+			err := r.ParseMultipartForm(1 << 20)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			json.NewEncoder(w).Encode(AsyncResponse{TaskID: r.MultipartForm.Value["page_range"][0], TaskStatus: "pending"})
+		case strings.HasPrefix(r.URL.Path, "/v1/status/poll/"):
+			json.NewEncoder(w).Encode(AsyncResponse{TaskID: path.Base(r.URL.Path), TaskStatus: "success"})
+		case strings.HasPrefix(r.URL.Path, "/v1/result/"):
+			from := path.Base(r.URL.Path)
+			if from == "3" && fail.Load() {
+				http.Error(w, "boom", http.StatusInternalServerError)
+				return
+			}
+			fmt.Fprintf(w, `{"status":"success","document":{"filename":"doc.pdf","md_content":"pages from %s"}}`, from)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	dir := t.TempDir()
+	c, err := NewClient(ClientConfig{BaseURL: srv.URL}, WithResponseSpooling(1, dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := ProcessFileRequest{
+		Files:          []File{FileReader{Filename: "doc.pdf", Reader: strings.NewReader("%PDF")}},
+		ConvertOptions: ConvertOptions{PageRange: PageRange{From: 1, To: 4}},
+	}
+	opts := ShardOptions{ShardSize: 2, PollInterval: time.Millisecond}
+	resp, err := c.ProcessFileSharded(context.Background(), req, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := resp.Document.MarkdownContent(); got != "pages from 1\n\npages from 3" {
+		t.Fatalf("unexpected markdown %q", got)
+	}
+	fail.Store(true)
+	req.Files = []File{FileReader{Filename: "doc.pdf", Reader: strings.NewReader("%PDF")}}
+	_, err = c.ProcessFileSharded(context.Background(), req, opts)
+	if err == nil {
+		t.Fatal("expected an error for a failed shard")
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("expected the spooled contents of the shards to be removed, got %d files", len(entries))
+	}
+}
@@ -0,0 +1,285 @@
+package docling
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// docTagsLocScale is the size of the grid location tokens are expressed in.
+const docTagsLocScale = 500
+
+var (
+	docTagsTokenRegexp = regexp.MustCompile(`<(/?)([A-Za-z0-9_]+)>`)
+	docTagsLocRegexp   = regexp.MustCompile(`^loc_(\d+)$`)
+	docTagsLevelRegexp = regexp.MustCompile(`^section_header_level_(\d+)$`)
+)
+
+// docTagsVoidTags are the tags which are never closed, besides the loc_<n>,
+// _<language>_ and OTSL tokens.
+var docTagsVoidTags = map[string]bool{
+	"page_break": true,
+}
+
+// docTagsTags are the element tags of DocTags, besides the
+// section_header_level_<n> ones. Other <...> sequences are kept as text.
+var docTagsTags = map[string]bool{
+	"doctag": true, "document": true, "title": true, "text": true, "paragraph": true,
+	"page_header": true, "page_footer": true, "caption": true, "footnote": true,
+	"reference": true, "code": true, "formula": true, "list_item": true,
+	"ordered_list": true, "unordered_list": true, "group": true, "inline": true,
+	"picture": true, "figure": true, "chart": true, "smiles": true, "otsl": true,
+	"table": true, "form": true, "key_value_region": true, "document_index": true,
+	"checkbox_selected": true, "checkbox_unselected": true,
+	// picture classes
+	"other": true, "picture_group": true, "pie_chart": true, "bar_chart": true,
+	"stacked_bar_chart": true, "line_chart": true, "flow_chart": true,
+	"scatter_chart": true, "heatmap": true, "remote_sensing": true,
+	"natural_image": true, "chemistry_molecular_structure": true,
+	"chemistry_markush_structure": true, "icon": true, "logo": true,
+	"signature": true, "stamp": true, "qr_code": true, "bar_code": true,
+	"screenshot": true, "map": true, "stratigraphic_chart": true,
+	"cad_drawing": true, "electrical_diagram": true,
+}
+
+// docTagsLiteralTags are the elements whose text is kept verbatim, only their
+// closing tag and the location and language tokens are markup inside them.
+var docTagsLiteralTags = map[string]bool{
+	"code":    true,
+	"formula": true,
+	"smiles":  true,
+}
+
+// LocBox is a location of a DocTags element, normalized to the page size with
+// the origin at the top left corner.
+type LocBox struct {
+	L, T, R, B float64
+}
+
+// Scale returns the box in the coordinates of a page of the given size.
+func (b LocBox) Scale(width, height float64) BoundingBox {
+	return BoundingBox{
+		L:           b.L * width,
+		T:           b.T * height,
+		R:           b.R * width,
+		B:           b.B * height,
+		CoordOrigin: CoordOriginTopLeft,
+	}
+}
+
+// DocTagsElement is an element of a DocTags document, such as a text, a
+// picture or a table.
+type DocTagsElement struct {
+	Tag      string  // "text" "title" "section_header_level_1" "picture" "otsl" "code" "formula" "list_item" ...
+	Page     int     // page of the element, numbered from 1
+	Loc      *LocBox // nil when the element has no location
+	Text     string
+	Level    int    // section headers
+	Language string // code
+	Table    *Table // otsl
+	Children []DocTagsElement
+}
+
+type DocTagsDocument struct {
+	Elements []DocTagsElement
+	Pages    int
+}
+
+// DocTags parses the doctags content of the document.
+func (d Document) DocTags() (*DocTagsDocument, error) {
+	content, ok := d.Content(ToDocTags)
+	if !ok {
+		return nil, errors.New("document has no doctags content")
+	}
+	s, err := readContent(content)
+	if err != nil {
+		return nil, err
+	}
+	return ParseDocTags(s)
+}
+
+// docTagsNode is an element being parsed, its parts are texts, tokens and
+// child nodes in document order.
+type docTagsNode struct {
+	tag   string
+	page  int
+	parts []any // string, docTagsToken or *docTagsNode
+}
+
+type docTagsToken string
+
+// ParseDocTags parses DocTags markup. Elements left open are closed by their
+// parent, and the elements they contain are moved up to the parent. Texts,
+// including the texts of table cells, are HTML unescaped, so &lt; and &amp;
+// decode as < and &.
+func ParseDocTags(s string) (*DocTagsDocument, error) {
+	root := &docTagsNode{}
+	stack := []*docTagsNode{root}
+	page := 1
+	last := 0
+	for _, m := range docTagsTokenRegexp.FindAllStringSubmatchIndex(s, -1) {
+		top := stack[len(stack)-1]
+		closing, tag := s[m[2]:m[3]] == "/", s[m[4]:m[5]]
+		if !isDocTagsMarkup(top.tag, tag, closing) {
+			continue
+		}
+		if m[0] > last {
+			top.parts = append(top.parts, s[last:m[0]])
+		}
+		last = m[1]
+		switch {
+		case closing:
+			i := len(stack) - 1
+			for i > 0 && stack[i].tag != tag {
+				i--
+			}
+			if i == 0 {
+				// stray closing tag
+				continue
+			}
+			for j := len(stack) - 1; j > i; j-- {
+				unclose(stack[j-1], stack[j])
+			}
+			stack = stack[:i]
+		case tag == "page_break":
+			page++
+		case isDocTagsVoid(tag):
+			top.parts = append(top.parts, docTagsToken(tag))
+		default:
+			n := &docTagsNode{tag: tag, page: page}
+			top.parts = append(top.parts, n)
+			stack = append(stack, n)
+		}
+	}
+	top := stack[len(stack)-1]
+	if last < len(s) {
+		top.parts = append(top.parts, s[last:])
+	}
+	for j := len(stack) - 1; j > 0; j-- {
+		unclose(stack[j-1], stack[j])
+	}
+	doc := &DocTagsDocument{Pages: page}
+	elements, err := docTagsElements(root)
+	if err != nil {
+		return nil, err
+	}
+	// the document is usually wrapped in a doctag element
+	if len(elements) == 1 && elements[0].Tag == "doctag" {
+		elements = elements[0].Children
+	}
+	doc.Elements = elements
+	return doc, nil
+}
+
+// isDocTagsMarkup reports whether a tag found in the element parent is
+// markup, rather than text such as the template of std::vector<int>.
+func isDocTagsMarkup(parent, tag string, closing bool) bool {
+	if docTagsLiteralTags[parent] {
+		if closing {
+			return tag == parent
+		}
+		return docTagsLocRegexp.MatchString(tag) || isDocTagsLanguage(tag)
+	}
+	return docTagsTags[tag] || docTagsLevelRegexp.MatchString(tag) || isDocTagsVoid(tag)
+}
+
+func isDocTagsLanguage(tag string) bool {
+	return len(tag) > 2 && strings.HasPrefix(tag, "_") && strings.HasSuffix(tag, "_")
+}
+
+func isDocTagsVoid(tag string) bool {
+	return docTagsVoidTags[tag] || isOTSLToken(tag) || docTagsLocRegexp.MatchString(tag) || isDocTagsLanguage(tag)
+}
+
+// unclose turns an element which was never closed into a token, and moves its
+// parts after it in its parent.
+func unclose(parent, n *docTagsNode) {
+	for i, part := range parent.parts {
+		if part == n {
+			parts := append([]any{docTagsToken(n.tag)}, n.parts...)
+			parent.parts = append(parent.parts[:i], append(parts, parent.parts[i+1:]...)...)
+			return
+		}
+	}
+}
+
+func docTagsElements(n *docTagsNode) ([]DocTagsElement, error) {
+	var elements []DocTagsElement
+	for _, part := range n.parts {
+		switch part := part.(type) {
+		case *docTagsNode:
+			e, err := docTagsElement(part)
+			if err != nil {
+				return nil, err
+			}
+			elements = append(elements, e)
+		case docTagsToken:
+			tag := string(part)
+			if !isDocTagsVoid(tag) {
+				// unclosed element, such as a picture class
+				elements = append(elements, DocTagsElement{Tag: tag, Page: n.page})
+			}
+		}
+	}
+	return elements, nil
+}
+
+func docTagsElement(n *docTagsNode) (DocTagsElement, error) {
+	e := DocTagsElement{Tag: n.tag, Page: n.page}
+	if m := docTagsLevelRegexp.FindStringSubmatch(n.tag); m != nil {
+		e.Level, _ = strconv.Atoi(m[1])
+	}
+	var (
+		locs   []float64
+		text   strings.Builder
+		tokens []otslToken
+	)
+	for _, part := range n.parts {
+		switch part := part.(type) {
+		case string:
+			if len(tokens) > 0 {
+				tokens[len(tokens)-1].text += part
+				continue
+			}
+			text.WriteString(part)
+		case docTagsToken:
+			tag := string(part)
+			switch {
+			case docTagsLocRegexp.MatchString(tag):
+				v, _ := strconv.Atoi(tag[len("loc_"):])
+				locs = append(locs, float64(v)/docTagsLocScale)
+			case isOTSLToken(tag):
+				tokens = append(tokens, otslToken{kind: tag})
+			case isDocTagsLanguage(tag):
+				e.Language = strings.Trim(tag, "_")
+			}
+		}
+	}
+	if len(locs) >= 4 {
+		e.Loc = &LocBox{L: locs[0], T: locs[1], R: locs[2], B: locs[3]}
+	}
+	e.Text = html.UnescapeString(strings.TrimSpace(text.String()))
+	if len(tokens) > 0 {
+		t, err := decodeOTSL(tokens)
+		if err != nil {
+			return DocTagsElement{}, fmt.Errorf("failed to decode table: %w", err)
+		}
+		e.Table = &t
+	}
+	children, err := docTagsElements(n)
+	if err != nil {
+		return DocTagsElement{}, err
+	}
+	e.Children = children
+	if e.Table != nil {
+		for _, c := range children {
+			if c.Tag == "caption" {
+				e.Table.Caption = c.Text
+			}
+		}
+	}
+	return e, nil
+}
@@ -0,0 +1,95 @@
+package docling
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseDocTags(t *testing.T) {
+	doc := Document{Contents: []Content{DocTagsContent(`<doctag><page_header><loc_0><loc_0><loc_500><loc_25>ACME</page_header>
+<section_header_level_2><loc_50><loc_50><loc_250><loc_75>Results</section_header_level_2>
+<text><loc_50><loc_100><loc_450><loc_150>Sales grew.</text>
+<otsl><loc_50><loc_200><loc_450><loc_300><ched>Region<ched>Sales<lcel><nl><fcel>North<fcel>1<ecel><nl><caption><loc_50><loc_310><loc_450><loc_320>Table 1</caption></otsl>
+<page_break>
+<picture><loc_10><loc_10><loc_100><loc_100><pie_chart><caption>Figure 1</caption></picture>
+<code><loc_10><loc_110><loc_100><loc_120><_Python_>print("hi")</code>
+<unordered_list><list_item><loc_10><loc_130><loc_100><loc_140>first</list_item></unordered_list>
+</doctag>`)}}
+	dt, err := doc.DocTags()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dt.Pages != 2 || len(dt.Elements) != 7 {
+		t.Fatalf("unexpected document %+v", dt)
+	}
+	header := dt.Elements[1]
+	if header.Level != 2 || header.Text != "Results" || *header.Loc != (LocBox{L: 0.1, T: 0.1, R: 0.5, B: 0.15}) {
+		t.Fatalf("unexpected section header %+v", header)
+	}
+	if box := header.Loc.Scale(1000, 2000); box.L != 100 || box.B != 300 || box.CoordOrigin != CoordOriginTopLeft {
+		t.Fatalf("unexpected scaled box %+v", box)
+	}
+	table := dt.Elements[3].Table
+	if table == nil || table.NumRows != 2 || table.NumCols != 3 || table.Caption != "Table 1" {
+		t.Fatalf("unexpected table %+v", table)
+	}
+	want := [][]string{{"Region", "Sales", "Sales"}, {"North", "1", ""}}
+	if rows := table.Rows(); !reflect.DeepEqual(rows, want) {
+		t.Fatalf("expected rows %q, got %q", want, rows)
+	}
+	picture := dt.Elements[4]
+	if picture.Page != 2 || len(picture.Children) != 2 || picture.Children[0].Tag != "pie_chart" || picture.Children[1].Text != "Figure 1" {
+		t.Fatalf("unexpected picture %+v", picture)
+	}
+	code := dt.Elements[5]
+	if code.Language != "Python" || code.Text != `print("hi")` {
+		t.Fatalf("unexpected code %+v", code)
+	}
+	list := dt.Elements[6]
+	if len(list.Children) != 1 || list.Children[0].Text != "first" {
+		t.Fatalf("unexpected list %+v", list)
+	}
+}
+
+func TestParseDocTagsLiteralText(t *testing.T) {
+	dt, err := ParseDocTags(`<doctag><code><loc_1><loc_2><loc_3><loc_4><_cpp_>std::vector<int> v; // </int></code>
+<formula>a <b> c</formula>
+<text>Use <br> or <custom_tag> here</text></doctag>`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dt.Elements) != 3 {
+		t.Fatalf("unexpected elements %+v", dt.Elements)
+	}
+	code := dt.Elements[0]
+	if code.Language != "cpp" || code.Text != "std::vector<int> v; // </int>" || code.Loc == nil || len(code.Children) != 0 {
+		t.Fatalf("unexpected code %+v", code)
+	}
+	if formula := dt.Elements[1]; formula.Text != "a <b> c" || len(formula.Children) != 0 {
+		t.Fatalf("unexpected formula %+v", formula)
+	}
+	if text := dt.Elements[2]; text.Text != "Use <br> or <custom_tag> here" || len(text.Children) != 0 {
+		t.Fatalf("unexpected text %+v", text)
+	}
+}
+
+func TestParseDocTagsUnescape(t *testing.T) {
+	dt, err := ParseDocTags(`<doctag><text><loc_10><loc_10><loc_100><loc_20>AT&amp;T &lt;3</text>
+<otsl><loc_10><loc_30><loc_100><loc_60><ched>Company<nl><fcel>AT&amp;T<nl><caption><loc_10><loc_70><loc_100><loc_80>AT&amp;T results</caption></otsl></doctag>`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dt.Elements) != 2 {
+		t.Fatalf("unexpected elements %+v", dt.Elements)
+	}
+	if text := dt.Elements[0].Text; text != "AT&T <3" {
+		t.Fatalf("unexpected text %q", text)
+	}
+	table := dt.Elements[1].Table
+	if table == nil || table.Caption != "AT&T results" || dt.Elements[1].Children[0].Text != "AT&T results" {
+		t.Fatalf("unexpected table %+v", dt.Elements[1])
+	}
+	if want := [][]string{{"Company"}, {"AT&T"}}; !reflect.DeepEqual(table.Rows(), want) {
+		t.Fatalf("expected rows %q, got %q", want, table.Rows())
+	}
+}
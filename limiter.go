@@ -0,0 +1,76 @@
+package docling
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// limiter is a FIFO semaphore: slots are handed to waiters in arrival order
+// so a burst of callers cannot starve an earlier one.
+type limiter struct {
+	mu      sync.Mutex
+	max     int
+	active  int
+	waiters list.List // of chan struct{}
+}
+
+func newLimiter(n int) *limiter {
+	if n <= 0 {
+		return nil
+	}
+	return &limiter{max: n}
+}
+
+func (l *limiter) acquire(ctx context.Context) (time.Duration, error) {
+	if l == nil {
+		return 0, nil
+	}
+	start := time.Now()
+	l.mu.Lock()
+	if l.active < l.max && l.waiters.Len() == 0 {
+		l.active++
+		l.mu.Unlock()
+		return 0, nil
+	}
+	ready := make(chan struct{})
+	elem := l.waiters.PushBack(ready)
+	l.mu.Unlock()
+	select {
+	case <-ready:
+		return time.Since(start), nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		select {
+		case <-ready:
+			// the slot was granted while we were giving up, pass it on
+			l.mu.Unlock()
+			l.release()
+		default:
+			l.waiters.Remove(elem)
+			l.mu.Unlock()
+		}
+		return time.Since(start), ctx.Err()
+	}
+}
+
+func (l *limiter) release() {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if front := l.waiters.Front(); front != nil {
+		l.waiters.Remove(front)
+		close(front.Value.(chan struct{}))
+		return
+	}
+	l.active--
+}
+
+func (l *limiter) queued() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.waiters.Len()
+}
@@ -0,0 +1,62 @@
+package docling
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLimiterFIFO(t *testing.T) {
+	l := newLimiter(1)
+	_, err := l.acquire(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	order := make(chan int, 3)
+	for i := range 3 {
+		go func() {
+			_, err := l.acquire(context.Background())
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			order <- i
+			l.release()
+		}()
+		// make sure the waiters are queued in order
+		for l.queued() != i+1 {
+			time.Sleep(time.Millisecond)
+		}
+	}
+	l.release()
+	for i := range 3 {
+		if got := <-order; got != i {
+			t.Fatalf("expected waiter %d, got %d", i, got)
+		}
+	}
+}
+
+func TestLimiterCancel(t *testing.T) {
+	l := newLimiter(1)
+	_, err := l.acquire(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	wait, err := l.acquire(ctx)
+	if err != context.DeadlineExceeded {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if wait < 10*time.Millisecond {
+		t.Fatalf("expected queue wait of at least 10ms, got %s", wait)
+	}
+	if l.queued() != 0 {
+		t.Fatal("cancelled waiter is still queued")
+	}
+	l.release()
+	_, err = l.acquire(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,318 @@
+// Package markdown post-processes the markdown content of converted
+// documents, as returned by Document.MarkdownContent.
+package markdown
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// ImagePlaceholder is the placeholder docling writes in place of images when
+// they are not embedded.
+const ImagePlaceholder = "<!-- image -->"
+
+var (
+	headingRegexp   = regexp.MustCompile(`^ {0,3}(#{1,6})[ \t]+(.*?)(?:[ \t]+#+)?[ \t]*$`)
+	imageRegexp     = regexp.MustCompile(`!\[([^\]]*)\]\(([^)\s]*)\)`)
+	delimiterRegexp = regexp.MustCompile(`^:?-+:?$`)
+	digitsRegexp    = regexp.MustCompile(`\d+`)
+)
+
+// SplitPages splits markdown converted with MDPageBreakPlaceholder set into
+// pages. The whole markdown is a single page when placeholder is empty.
+func SplitPages(md, placeholder string) []string {
+	if placeholder == "" {
+		return []string{strings.TrimSpace(md)}
+	}
+	pages := strings.Split(md, placeholder)
+	for i, page := range pages {
+		pages[i] = strings.TrimSpace(page)
+	}
+	return pages
+}
+
+// Heading is a heading of a markdown document.
+type Heading struct {
+	Level  int
+	Text   string
+	Anchor string // GitHub style anchor, unique in the document
+	Line   int    // numbered from 1
+}
+
+// Outline returns the ATX headings of the markdown, headings in code blocks
+// are ignored.
+func Outline(md string) []Heading {
+	var (
+		headings []Heading
+		anchors  = make(map[string]int)
+	)
+	eachLine(md, func(i int, line string, code bool) {
+		if code {
+			return
+		}
+		m := headingRegexp.FindStringSubmatch(line)
+		if m == nil {
+			return
+		}
+		h := Heading{Level: len(m[1]), Text: m[2], Line: i + 1}
+		h.Anchor = anchor(h.Text)
+		if n := anchors[h.Anchor]; n > 0 {
+			anchors[h.Anchor]++
+			h.Anchor = fmt.Sprintf("%s-%d", h.Anchor, n)
+		} else {
+			anchors[h.Anchor] = 1
+		}
+		headings = append(headings, h)
+	})
+	return headings
+}
+
+// anchor returns the anchor GitHub generates for a heading.
+func anchor(text string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case unicode.IsLetter(r), unicode.IsDigit(r), r == '-', r == '_':
+			b.WriteRune(r)
+		case r == ' ':
+			b.WriteRune('-')
+		}
+	}
+	return b.String()
+}
+
+// TOC renders the headings as a nested list of links to their anchors, the
+// headings of the lowest level being at the top.
+func TOC(headings []Heading) string {
+	if len(headings) == 0 {
+		return ""
+	}
+	top := headings[0].Level
+	for _, h := range headings {
+		top = min(top, h.Level)
+	}
+	var b strings.Builder
+	for _, h := range headings {
+		b.WriteString(strings.Repeat("  ", h.Level-top))
+		fmt.Fprintf(&b, "- [%s](#%s)\n", strings.NewReplacer("[", `\[`, "]", `\]`).Replace(h.Text), h.Anchor)
+	}
+	return b.String()
+}
+
+// ReplaceImages replaces the images of the markdown with the result of fn,
+// which gets their alternative text and URI.
+func ReplaceImages(md string, fn func(alt, uri string) string) string {
+	return imageRegexp.ReplaceAllStringFunc(md, func(s string) string {
+		m := imageRegexp.FindStringSubmatch(s)
+		return fn(m[1], m[2])
+	})
+}
+
+// StripImageData replaces the images embedded as data URIs with placeholder,
+// such as ImagePlaceholder. They are removed when placeholder is empty.
+func StripImageData(md, placeholder string) string {
+	return ReplaceImages(md, func(alt, uri string) string {
+		if !strings.HasPrefix(uri, "data:") {
+			return "![" + alt + "](" + uri + ")"
+		}
+		return placeholder
+	})
+}
+
+// NormalizeTables rewrites the tables of the markdown in a compact form: cells
+// are trimmed, rows are padded to the same number of columns, and a delimiter
+// row is added when missing. Column alignments are kept.
+func NormalizeTables(md string) string {
+	var (
+		out   []string
+		table [][]string
+	)
+	flush := func() {
+		if len(table) > 0 {
+			out = append(out, normalizeTable(table)...)
+			table = nil
+		}
+	}
+	eachLine(md, func(_ int, line string, code bool) {
+		trimmed := strings.TrimSpace(line)
+		if !code && strings.HasPrefix(trimmed, "|") {
+			table = append(table, splitRow(trimmed))
+			return
+		}
+		flush()
+		out = append(out, line)
+	})
+	flush()
+	return strings.Join(out, "\n")
+}
+
+// splitRow returns the cells of a table row, pipes escaped with a backslash
+// are part of the cells.
+func splitRow(row string) []string {
+	row = strings.TrimPrefix(row, "|")
+	if strings.HasSuffix(row, "|") && !strings.HasSuffix(row, `\|`) {
+		row = row[:len(row)-1]
+	}
+	var (
+		cells []string
+		cell  strings.Builder
+	)
+	for i := 0; i < len(row); i++ {
+		switch {
+		case row[i] == '\\' && i+1 < len(row) && row[i+1] == '|':
+			cell.WriteString(`\|`)
+			i++
+		case row[i] == '|':
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+		default:
+			cell.WriteByte(row[i])
+		}
+	}
+	return append(cells, strings.TrimSpace(cell.String()))
+}
+
+func normalizeTable(rows [][]string) []string {
+	var (
+		cols  int
+		align []string
+	)
+	if len(rows) > 1 && isDelimiterRow(rows[1]) {
+		align = rows[1]
+		rows = append(rows[:1], rows[2:]...)
+	}
+	for _, row := range rows {
+		cols = max(cols, len(row))
+	}
+	delimiter := make([]string, cols)
+	for i := range delimiter {
+		delimiter[i] = "---"
+		if i < len(align) {
+			left, right := strings.HasPrefix(align[i], ":"), strings.HasSuffix(align[i], ":")
+			switch {
+			case left && right:
+				delimiter[i] = ":---:"
+			case left:
+				delimiter[i] = ":---"
+			case right:
+				delimiter[i] = "---:"
+			}
+		}
+	}
+	lines := make([]string, 0, len(rows)+1)
+	for i, row := range rows {
+		for len(row) < cols {
+			row = append(row, "")
+		}
+		lines = append(lines, "| "+strings.Join(row, " | ")+" |")
+		if i == 0 {
+			lines = append(lines, "|"+strings.Join(delimiter, "|")+"|")
+		}
+	}
+	return lines
+}
+
+func isDelimiterRow(row []string) bool {
+	for _, cell := range row {
+		if !delimiterRegexp.MatchString(cell) {
+			return false
+		}
+	}
+	return true
+}
+
+// RemoveBoilerplate removes the page headers and footers from pages as
+// returned by SplitPages: the first and last two lines of a page are removed
+// when they are found at the start or end of at least half of the pages,
+// numbers being ignored so that page numbers match. Headings, table rows,
+// images and code are never removed. Nothing is removed with less than 3
+// pages.
+func RemoveBoilerplate(pages []string) []string {
+	const edge = 2
+	if len(pages) < 3 {
+		return pages
+	}
+	count := make(map[string]int)
+	edges := make([][]int, len(pages))
+	lines := make([][]string, len(pages))
+	for i, page := range pages {
+		lines[i] = strings.Split(page, "\n")
+		var nonBlank []int
+		content := make(map[int]bool)
+		eachLine(page, func(j int, line string, code bool) {
+			if strings.TrimSpace(line) == "" {
+				return
+			}
+			nonBlank = append(nonBlank, j)
+			content[j] = code || isContentLine(line)
+		})
+		if len(nonBlank) > 2*edge {
+			nonBlank = append(nonBlank[:edge], nonBlank[len(nonBlank)-edge:]...)
+		}
+		for _, j := range nonBlank {
+			if !content[j] {
+				edges[i] = append(edges[i], j)
+			}
+		}
+		seen := make(map[string]bool)
+		for _, j := range edges[i] {
+			key := boilerplateKey(lines[i][j])
+			if !seen[key] {
+				seen[key] = true
+				count[key]++
+			}
+		}
+	}
+	result := make([]string, len(pages))
+	for i := range pages {
+		remove := make(map[int]bool)
+		for _, j := range edges[i] {
+			if 2*count[boilerplateKey(lines[i][j])] >= len(pages) {
+				remove[j] = true
+			}
+		}
+		kept := make([]string, 0, len(lines[i]))
+		for j, line := range lines[i] {
+			if !remove[j] {
+				kept = append(kept, line)
+			}
+		}
+		result[i] = strings.TrimSpace(strings.Join(kept, "\n"))
+	}
+	return result
+}
+
+// isContentLine reports whether a line is part of the content of a page even
+// when repeated, such as the row of a table split across pages.
+func isContentLine(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return headingRegexp.MatchString(line) || strings.HasPrefix(trimmed, "|") ||
+		strings.Contains(trimmed, ImagePlaceholder) || imageRegexp.MatchString(trimmed)
+}
+
+func boilerplateKey(line string) string {
+	return digitsRegexp.ReplaceAllString(strings.TrimSpace(line), "#")
+}
+
+// eachLine calls fn for every line of md, telling whether the line is part of
+// a fenced code block.
+func eachLine(md string, fn func(i int, line string, code bool)) {
+	var fence string
+	for i, line := range strings.Split(md, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case fence == "" && (strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~")):
+			fence = trimmed[:3]
+			fn(i, line, true)
+		case fence != "":
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			fn(i, line, true)
+		default:
+			fn(i, line, false)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package markdown
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const placeholder = "<!-- page break -->"
+
+func TestSplitPagesAndRemoveBoilerplate(t *testing.T) {
+	md := strings.Join([]string{
+		"ACME Corp annual report\n\n# Introduction\n\nFirst page.\n\nPage 1",
+		"ACME Corp annual report\n\n## Results\n\nSecond page.\n\nPage 2",
+		"ACME Corp annual report\n\nThird page.\n\nPage 3",
+	}, "\n\n"+placeholder+"\n\n")
+	pages := SplitPages(md, placeholder)
+	if len(pages) != 3 {
+		t.Fatalf("expected 3 pages, got %d", len(pages))
+	}
+	if got := SplitPages(md, ""); len(got) != 1 {
+		t.Fatalf("expected a single page without placeholder, got %d", len(got))
+	}
+	want := []string{
+		"# Introduction\n\nFirst page.",
+		"## Results\n\nSecond page.",
+		"Third page.",
+	}
+	if got := RemoveBoilerplate(pages); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+	if got := RemoveBoilerplate(pages[:2]); !reflect.DeepEqual(got, pages[:2]) {
+		t.Fatalf("expected pages to be kept, got %q", got)
+	}
+}
+
+func TestOutlineAndTOC(t *testing.T) {
+	md := "## Introduction\n\ntext\n\n```\n# not a heading\n```\n\n### Scope & Goals ###\n\n## Introduction\n"
+	want := []Heading{
+		{Level: 2, Text: "Introduction", Anchor: "introduction", Line: 1},
+		{Level: 3, Text: "Scope & Goals", Anchor: "scope--goals", Line: 9},
+		{Level: 2, Text: "Introduction", Anchor: "introduction-1", Line: 11},
+	}
+	headings := Outline(md)
+	if !reflect.DeepEqual(headings, want) {
+		t.Fatalf("expected %+v, got %+v", want, headings)
+	}
+	toc := "- [Introduction](#introduction)\n  - [Scope & Goals](#scope--goals)\n- [Introduction](#introduction-1)\n"
+	if got := TOC(headings); got != toc {
+		t.Fatalf("expected %q, got %q", toc, got)
+	}
+}
+
+func TestStripImageData(t *testing.T) {
+	md := "Before ![Figure 1](data:image/png;base64,iVBORw0KGgo=) and ![logo](logo.png)."
+	want := "Before " + ImagePlaceholder + " and ![logo](logo.png)."
+	if got := StripImageData(md, ImagePlaceholder); got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+	if got := StripImageData(md, ""); got != "Before  and ![logo](logo.png)." {
+		t.Fatalf("unexpected stripped markdown %q", got)
+	}
+}
+
+func TestNormalizeTables(t *testing.T) {
+	md := "Text\n\n| Name    |   Value |\n|:--------|--------:|\n| a \\| b  | 1 |\n| c |\n\n```\n| kept   |\n```"
+	want := "Text\n\n| Name | Value |\n|:---|---:|\n| a \\| b | 1 |\n| c |  |\n\n```\n| kept   |\n```"
+	if got := NormalizeTables(md); got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+	if got := NormalizeTables("| a | b |\n| 1 | 2 |"); got != "| a | b |\n|---|---|\n| 1 | 2 |" {
+		t.Fatalf("expected a delimiter row to be added, got %q", got)
+	}
+}
+
+func TestRemoveBoilerplateKeepsContent(t *testing.T) {
+	table := "| Region | Sales |\n|---|---|\n| North | 1 |"
+	pages := []string{
+		"ACME\n\n## Sales\n\n" + table + "\n\n- 1 -",
+		"ACME\n\n## Sales\n\n" + table + "\n\n- 2 -",
+		"ACME\n\n" + ImagePlaceholder + "\n\n![Logo](logo.png)\n\n- 3 -",
+		"ACME\n\n" + ImagePlaceholder + "\n\n![Logo](logo.png)\n\n- 4 -",
+	}
+	want := []string{
+		"## Sales\n\n" + table,
+		"## Sales\n\n" + table,
+		ImagePlaceholder + "\n\n![Logo](logo.png)",
+		ImagePlaceholder + "\n\n![Logo](logo.png)",
+	}
+	if got := RemoveBoilerplate(pages); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
@@ -39,7 +39,22 @@ func multipartEncode(w *multipart.Writer, s any) error {
 	return nil
 }
 
+// formValuer is implemented by types that are not sent as a single JSON
+// encoded field, each returned value is written as its own form field.
+type formValuer interface {
+	formValues() []string
+}
+
 func writeValue(w *multipart.Writer, name string, v reflect.Value) error {
+	if fv, ok := asFormValuer(v); ok {
+		for _, value := range fv.formValues() {
+			err := w.WriteField(name, value)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
 	switch v.Kind() {
 	case reflect.Bool:
 		ff, err := w.CreateFormField(name)
@@ -153,3 +168,11 @@ func isEmptyValue(v reflect.Value) bool {
 	}
 	return false
 }
+
+func asFormValuer(v reflect.Value) (formValuer, bool) {
+	if !v.IsValid() || !v.CanInterface() {
+		return nil, false
+	}
+	fv, ok := v.Interface().(formValuer)
+	return fv, ok
+}
@@ -142,6 +142,16 @@ func TestMultipartEncode(t *testing.T) {
 			boundaries: 1,
 			expected:   "--%s\r\nContent-Disposition: form-data; name=\"struct\"\r\n\r\n{\"foo\":\"bar\"}",
 		},
+		{
+			name: "FormValuer",
+			s: struct {
+				PageRange PageRange `json:"page_range"`
+			}{
+				PageRange: PageRange{From: 2},
+			},
+			boundaries: 2,
+			expected:   "--%s\r\nContent-Disposition: form-data; name=\"page_range\"\r\n\r\n2\r\n--%s\r\nContent-Disposition: form-data; name=\"page_range\"\r\n\r\n9223372036854775807",
+		},
 		{
 			name: "OmitZero",
 			s: struct {
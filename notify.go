@@ -0,0 +1,238 @@
+package docling
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// StatusTransport reports the status of async tasks to a Notifier. The only
+// transport provided is the Client, which polls the status endpoint; this
+// package has no WebSocket transport. A push based implementation would
+// return as soon as an update is received or wait elapses.
+type StatusTransport interface {
+	TaskStatus(ctx context.Context, taskID string, wait time.Duration) (AsyncResponse, error)
+}
+
+// TaskStatus implements StatusTransport by polling, long-polling when wait is
+// set.
+func (c *Client) TaskStatus(ctx context.Context, taskID string, wait time.Duration) (AsyncResponse, error) {
+	return c.PollTaskStatusWait(ctx, taskID, wait)
+}
+
+type NotifierOptions struct {
+	Transport    StatusTransport // default: the client
+	PollInterval time.Duration   // default: 1s, pause between two rounds of status requests
+	LongPollWait time.Duration   // default: 0, long-poll wait used while a single task is watched
+	Concurrency  int             // default: 8, status requests in flight during a round
+}
+
+// TaskEvent is the final status of a watched task.
+type TaskEvent struct {
+	Status AsyncResponse
+	Err    error
+}
+
+// Notifier watches many async tasks, and notifies the subscribers of a task
+// once it succeeds or fails. The status requests of a round run concurrently,
+// so a slow request does not delay the notification of the other tasks.
+// Callbacks run on the goroutine calling Run and must not block.
+type Notifier struct {
+	opts   NotifierOptions
+	logger *slog.Logger
+
+	mu    sync.Mutex
+	tasks map[string]*watchedTask
+	order []string
+	wake  chan struct{}
+}
+
+type watchedTask struct {
+	subs map[int]func(TaskEvent)
+	next int
+}
+
+// NewNotifier returns a notifier watching tasks of the client, it does nothing
+// until Run is called.
+func (c *Client) NewNotifier(opts NotifierOptions) *Notifier {
+	if opts.Transport == nil {
+		opts.Transport = c
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = time.Second
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 8
+	}
+	return &Notifier{
+		opts:   opts,
+		logger: c.logger,
+		tasks:  make(map[string]*watchedTask),
+		wake:   make(chan struct{}, 1),
+	}
+}
+
+// Subscribe calls fn once the task completes, the returned function cancels
+// the subscription.
+func (n *Notifier) Subscribe(taskID string, fn func(TaskEvent)) (cancel func()) {
+	n.mu.Lock()
+	t, ok := n.tasks[taskID]
+	if !ok {
+		t = &watchedTask{subs: make(map[int]func(TaskEvent))}
+		n.tasks[taskID] = t
+		n.order = append(n.order, taskID)
+	}
+	id := t.next
+	t.next++
+	t.subs[id] = fn
+	n.mu.Unlock()
+	select {
+	case n.wake <- struct{}{}:
+	default:
+	}
+	return func() {
+		n.mu.Lock()
+		defer n.mu.Unlock()
+		if n.tasks[taskID] == t {
+			delete(t.subs, id)
+		}
+	}
+}
+
+// Watch returns a channel receiving the final status of the task.
+func (n *Notifier) Watch(taskID string) <-chan TaskEvent {
+	ch := make(chan TaskEvent, 1)
+	n.Subscribe(taskID, func(ev TaskEvent) {
+		ch <- ev
+	})
+	return ch
+}
+
+// Wait blocks until the task completes.
+func (n *Notifier) Wait(ctx context.Context, taskID string) (AsyncResponse, error) {
+	ch := make(chan TaskEvent, 1)
+	cancel := n.Subscribe(taskID, func(ev TaskEvent) {
+		ch <- ev
+	})
+	defer cancel()
+	select {
+	case ev := <-ch:
+		return ev.Status, ev.Err
+	case <-ctx.Done():
+		return AsyncResponse{}, ctx.Err()
+	}
+}
+
+// Run watches the subscribed tasks until ctx is done.
+func (n *Notifier) Run(ctx context.Context) error {
+	t := time.NewTimer(0)
+	defer t.Stop()
+	for {
+		n.mu.Lock()
+		ids := n.prune()
+		n.mu.Unlock()
+		if len(ids) == 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-n.wake:
+				continue
+			}
+		}
+		var wait time.Duration
+		if len(ids) == 1 {
+			wait = n.opts.LongPollWait
+		}
+		for res := range n.poll(ctx, ids, wait) {
+			var httpErr HTTPError
+			switch {
+			case errors.As(res.err, &httpErr) && httpErr.StatusCode == http.StatusNotFound:
+				n.notify(res.id, TaskEvent{Err: res.err})
+			case res.err != nil:
+				if ctx.Err() == nil {
+					n.logger.WarnContext(ctx, "failed to get task status", slog.String("task_id", res.id), slog.Any("error", res.err))
+				}
+			case res.status.TaskStatus == "success" || res.status.TaskStatus == "failure":
+				n.notify(res.id, TaskEvent{Status: res.status})
+			}
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		t.Reset(n.opts.PollInterval)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+type polledStatus struct {
+	id     string
+	status AsyncResponse
+	err    error
+}
+
+// poll requests the status of the tasks with at most Concurrency requests in
+// flight, the returned channel receives the statuses as they come and is
+// closed once every request returned.
+func (n *Notifier) poll(ctx context.Context, ids []string, wait time.Duration) <-chan polledStatus {
+	results := make(chan polledStatus, len(ids))
+	sem := make(chan struct{}, n.opts.Concurrency)
+	var wg sync.WaitGroup
+	for _, id := range ids {
+		wg.Go(func() {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			if ctx.Err() != nil {
+				results <- polledStatus{id: id, err: ctx.Err()}
+				return
+			}
+			status, err := n.opts.Transport.TaskStatus(ctx, id, wait)
+			results <- polledStatus{id: id, status: status, err: err}
+		})
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+	return results
+}
+
+// prune drops the completed tasks and those left without subscribers, and
+// returns the others.
+func (n *Notifier) prune() []string {
+	ids := n.order[:0]
+	seen := make(map[string]bool, len(n.order))
+	for _, id := range n.order {
+		t, ok := n.tasks[id]
+		if !ok || seen[id] {
+			continue
+		}
+		if len(t.subs) == 0 {
+			delete(n.tasks, id)
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	n.order = ids
+	return append([]string(nil), ids...)
+}
+
+func (n *Notifier) notify(taskID string, ev TaskEvent) {
+	n.mu.Lock()
+	t := n.tasks[taskID]
+	delete(n.tasks, taskID)
+	n.mu.Unlock()
+	if t == nil {
+		return
+	}
+	for _, fn := range t.subs {
+		fn(ev)
+	}
+}
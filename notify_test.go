@@ -0,0 +1,109 @@
+package docling
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNotifier(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		polls = make(map[string]int)
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, ok := strings.CutPrefix(r.URL.Path, "/v1/status/poll/")
+		if !ok || id == "missing" {
+			http.NotFound(w, r)
+			return
+		}
+		mu.Lock()
+		polls[id]++
+		status := "started"
+		if polls[id] >= 3 {
+			status = "success"
+		}
+		mu.Unlock()
+		json.NewEncoder(w).Encode(AsyncResponse{TaskID: id, TaskStatus: status})
+	}))
+	defer srv.Close()
+	c, err := NewClient(ClientConfig{BaseURL: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := c.NewNotifier(NotifierOptions{PollInterval: time.Millisecond})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	go n.Run(ctx)
+	var chans []<-chan TaskEvent
+	for i := range 100 {
+		id := fmt.Sprintf("task-%d", i)
+		chans = append(chans, n.Watch(id), n.Watch(id))
+	}
+	for _, ch := range chans {
+		select {
+		case ev := <-ch:
+			if ev.Err != nil || ev.Status.TaskStatus != "success" {
+				t.Fatalf("unexpected event %+v", ev)
+			}
+		case <-ctx.Done():
+			t.Fatal(ctx.Err())
+		}
+	}
+	_, err = n.Wait(ctx, "missing")
+	if err == nil {
+		t.Fatal("expected an error for a missing task")
+	}
+	for id, count := range polls {
+		if count != 3 {
+			t.Fatalf("task %s polled %d times", id, count)
+		}
+	}
+}
+
+type slowTransport struct {
+	release chan struct{}
+}
+
+func (st slowTransport) TaskStatus(ctx context.Context, taskID string, wait time.Duration) (AsyncResponse, error) {
+	if taskID == "slow" {
+		select {
+		case <-st.release:
+		case <-ctx.Done():
+			return AsyncResponse{}, ctx.Err()
+		}
+	}
+	return AsyncResponse{TaskID: taskID, TaskStatus: "success"}, nil
+}
+
+func TestNotifierSlowStatus(t *testing.T) {
+	c, err := NewClient(ClientConfig{BaseURL: "http://127.0.0.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	st := slowTransport{release: make(chan struct{})}
+	n := c.NewNotifier(NotifierOptions{Transport: st, PollInterval: time.Millisecond})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	slow := n.Watch("slow")
+	fast := n.Watch("fast")
+	go n.Run(ctx)
+	select {
+	case ev := <-fast:
+		if ev.Status.TaskStatus != "success" {
+			t.Fatalf("unexpected event %+v", ev)
+		}
+	case <-ctx.Done():
+		t.Fatal("fast task delayed by the slow one")
+	}
+	close(st.release)
+	if ev := <-slow; ev.Status.TaskStatus != "success" {
+		t.Fatalf("unexpected event %+v", ev)
+	}
+}
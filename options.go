@@ -12,7 +12,7 @@ type ConvertOptions struct {
 	TableMode                       TableMode                `json:"table_mode,omitempty"`                         // default: "accurate"
 	TableCellMatching               *bool                    `json:"table_cell_matching,omitempty"`                // default: true
 	Pipeline                        Pipeline                 `json:"pipeline,omitempty"`                           // default: "standard"
-	PageRange                       []int                    `json:"page_range,omitempty"`                         // default: [1,9223372036854776000]
+	PageRange                       PageRange                `json:"page_range,omitzero"`                          // default: [1,9223372036854776000]
 	DocumentTimeout                 *int                     `json:"document_timeout,omitempty"`                   // default: 604800
 	AbortOnError                    bool                     `json:"abort_on_error,omitempty"`                     // default: false
 	DoTableStructure                *bool                    `json:"do_table_structure,omitempty"`                 // default: true
@@ -99,9 +99,9 @@ func WithPipeline(pipeline Pipeline) ConvertOption {
 	}
 }
 
-func WithPageRange(pages ...int) ConvertOption {
+func WithPageRange(from, to int) ConvertOption {
 	return func(o *ConvertOptions) {
-		o.PageRange = append(o.PageRange, pages...)
+		o.PageRange = PageRange{From: from, To: to}
 	}
 }
 
@@ -226,7 +226,7 @@ const (
 	ToMarkdown      ToFormat = "md"
 	ToJSON          ToFormat = "json"
 	ToHTML          ToFormat = "html"
-	ToHTMLSplitPage ToFormat = "html_split_page"
+	ToHTMLSplitPage ToFormat = "html_split_page" // sent in html_content, see Document.Content
 	ToText          ToFormat = "text"
 	ToDocTags       ToFormat = "doctags"
 )
@@ -0,0 +1,123 @@
+package docling
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestConvertOptionsHash(t *testing.T) {
+	cases := []struct {
+		name  string
+		a     ConvertOptions
+		b     ConvertOptions
+		equal bool
+	}{
+		{
+			name:  "ExplicitDefaults",
+			a:     ConvertOptions{},
+			b:     ConvertOptions{ToFormats: []ToFormat{ToMarkdown}, DoOCR: Ptr(true), TableMode: TableModeAccurate, ImagesScale: Ptr(2.0)},
+			equal: true,
+		},
+		{
+			name:  "FormatOrder",
+			a:     ConvertOptions{ToFormats: []ToFormat{ToMarkdown, ToJSON}},
+			b:     ConvertOptions{ToFormats: []ToFormat{ToJSON, ToMarkdown, ToJSON}},
+			equal: true,
+		},
+		{
+			name:  "NestedDefaults",
+			a:     ConvertOptions{PictureDescriptionAPI: &PictureDescriptionAPI{URL: "http://vlm"}},
+			b:     ConvertOptions{PictureDescriptionAPI: &PictureDescriptionAPI{URL: "http://vlm", Concurrency: 1, Timeout: 20}},
+			equal: true,
+		},
+		{
+			name:  "DifferentValue",
+			a:     ConvertOptions{},
+			b:     ConvertOptions{DoOCR: Ptr(false)},
+			equal: false,
+		},
+		{
+			name:  "OCRLangOrder",
+			a:     ConvertOptions{OCRLang: []string{"en", "fr"}},
+			b:     ConvertOptions{OCRLang: []string{"fr", "en"}},
+			equal: false,
+		},
+	}
+	for _, c := range cases {
+		a, err := c.a.Hash()
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := c.b.Hash()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if (a == b) != c.equal {
+			t.Fatalf("%s: expected equal hashes to be %t, got %s and %s", c.name, c.equal, a, b)
+		}
+	}
+}
+
+func TestConvertOptionsValidate(t *testing.T) {
+	cases := []struct {
+		name   string
+		opts   ConvertOptions
+		fields []string
+	}{
+		{
+			name: "Valid",
+			opts: ConvertOptions{PageRange: PageRange{From: 1, To: 10}, ForceOCR: true},
+		},
+		{
+			name:   "PageRangeOrder",
+			opts:   ConvertOptions{PageRange: PageRange{From: 10, To: 1}},
+			fields: []string{"page_range"},
+		},
+		{
+			name:   "ForceOCR",
+			opts:   ConvertOptions{ForceOCR: true, DoOCR: Ptr(false)},
+			fields: []string{"force_ocr"},
+		},
+		{
+			name: "PictureDescription",
+			opts: ConvertOptions{
+				PictureDescriptionLocal: &PictureDescriptionLocal{},
+				PictureDescriptionAPI:   &PictureDescriptionAPI{URL: "http://vlm"},
+			},
+			fields: []string{"picture_description_api", "picture_description_local.repo_id"},
+		},
+		{
+			name:   "VLMWithoutPipeline",
+			opts:   ConvertOptions{VLMPipelineModelAPI: &VLMPipelineModelAPI{URL: "http://vlm"}},
+			fields: []string{"vlm_pipeline_model_api", "vlm_pipeline_model_api.response_format"},
+		},
+	}
+	for _, c := range cases {
+		err := c.opts.Validate()
+		var fields []string
+		if err != nil {
+			for _, err := range err.(interface{ Unwrap() []error }).Unwrap() {
+				fields = append(fields, err.(FieldError).Field)
+			}
+		}
+		if !slices.Equal(fields, c.fields) {
+			t.Fatalf("%s: expected errors on %v, got %v", c.name, c.fields, err)
+		}
+	}
+}
+
+func TestPageRangeSplit(t *testing.T) {
+	shards, err := PageRange{From: 1, To: 10}.Split(4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []PageRange{{1, 4}, {5, 8}, {9, 10}}; !slices.Equal(shards, want) {
+		t.Fatalf("expected %v, got %v", want, shards)
+	}
+	for _, r := range []PageRange{{From: 1}, {From: 1, To: math.MaxInt64}, {From: 5, To: 2}, {From: 1, To: math.MaxInt32}} {
+		if _, err := r.Split(1); err == nil {
+			t.Errorf("expected an error splitting %v", r)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package docling
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"strings"
+)
+
+// otslToken is a cell token of an OTSL table followed by its text.
+type otslToken struct {
+	kind string // "fcel" "ecel" "lcel" "ucel" "xcel" "ched" "rhed" "srow" "nl"
+	text string
+}
+
+func isOTSLToken(tag string) bool {
+	switch tag {
+	case "fcel", "ecel", "lcel", "ucel", "xcel", "ched", "rhed", "srow", "nl":
+		return true
+	}
+	return false
+}
+
+// decodeOTSL builds a table from OTSL tokens: cells are created by fcel, ecel,
+// ched, rhed and srow, and extended to the right by lcel, downwards by ucel,
+// and both ways by xcel.
+func decodeOTSL(tokens []otslToken) (Table, error) {
+	var (
+		t     Table
+		owner [][]int // index of the cell covering each position
+		row   []int
+	)
+	endRow := func() {
+		owner = append(owner, row)
+		t.NumCols = max(t.NumCols, len(row))
+		row = nil
+	}
+	for _, tok := range tokens {
+		r, c := len(owner), len(row)
+		switch tok.kind {
+		case "nl":
+			endRow()
+			continue
+		case "lcel", "ucel", "xcel":
+			i := -1
+			switch {
+			case tok.kind == "lcel" && c > 0:
+				i = row[c-1]
+			case tok.kind != "lcel" && r > 0 && c < len(owner[r-1]):
+				i = owner[r-1][c]
+			case tok.kind == "xcel" && c > 0:
+				i = row[c-1]
+			}
+			if i < 0 {
+				return Table{}, fmt.Errorf("otsl: %s token at row %d, column %d has no cell to extend", tok.kind, r, c)
+			}
+			cell := &t.Cells[i]
+			cell.EndRow = max(cell.EndRow, r+1)
+			cell.EndCol = max(cell.EndCol, c+1)
+			row = append(row, i)
+			continue
+		}
+		cell := TableCell{
+			StartRow:     r,
+			EndRow:       r + 1,
+			StartCol:     c,
+			EndCol:       c + 1,
+			Text:         html.UnescapeString(strings.TrimSpace(tok.text)),
+			ColumnHeader: tok.kind == "ched",
+			RowHeader:    tok.kind == "rhed",
+			RowSection:   tok.kind == "srow",
+		}
+		if tok.kind == "ecel" {
+			cell.Text = ""
+		}
+		row = append(row, len(t.Cells))
+		t.Cells = append(t.Cells, cell)
+	}
+	if len(row) > 0 {
+		endRow()
+	}
+	t.NumRows = len(owner)
+	for i := range t.Cells {
+		c := &t.Cells[i]
+		c.RowSpan = c.EndRow - c.StartRow
+		c.ColSpan = c.EndCol - c.StartCol
+	}
+	return t, nil
+}
+
+// ParseOTSL decodes an OTSL table, such as the output of a VLM pipeline with
+// ResponseFormatOTSL. The tokens may be wrapped in an otsl element, with
+// location tokens and a caption. As with ParseDocTags, cell texts are HTML
+// unescaped, which Table.OTSL relies on to escape them.
+func ParseOTSL(s string) (Table, error) {
+	if !strings.Contains(s, "<otsl>") {
+		s = "<otsl>" + s + "</otsl>"
+	}
+	doc, err := ParseDocTags(s)
+	if err != nil {
+		return Table{}, err
+	}
+	for _, e := range doc.Elements {
+		if e.Tag == "otsl" && e.Table != nil {
+			return *e.Table, nil
+		}
+	}
+	return Table{}, errors.New("otsl: no table found")
+}
+
+// otslEscaper escapes the cell texts, so a text holding a token such as <fcel>
+// is not read back as markup. Texts are unescaped when decoded.
+var otslEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// OTSL encodes the table as OTSL tokens wrapped in an otsl element. Empty
+// cells are encoded as ecel, so a full cell without text decodes as an empty
+// one.
+func (t Table) OTSL() string {
+	var b strings.Builder
+	b.WriteString("<otsl>")
+	for r, cells := range t.Grid() {
+		for c, cell := range cells {
+			switch {
+			case cell == nil:
+				b.WriteString("<ecel>")
+			case cell.StartRow == r && cell.StartCol == c:
+				kind := "fcel"
+				switch {
+				case cell.ColumnHeader:
+					kind = "ched"
+				case cell.RowHeader:
+					kind = "rhed"
+				case cell.RowSection:
+					kind = "srow"
+				case cell.Text == "":
+					kind = "ecel"
+				}
+				b.WriteString("<" + kind + ">")
+				b.WriteString(otslEscaper.Replace(cell.Text))
+			case cell.StartRow == r:
+				b.WriteString("<lcel>")
+			case cell.StartCol == c:
+				b.WriteString("<ucel>")
+			default:
+				b.WriteString("<xcel>")
+			}
+		}
+		b.WriteString("<nl>")
+	}
+	b.WriteString("</otsl>")
+	return b.String()
+}
@@ -0,0 +1,60 @@
+package docling
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOTSLRoundTrip(t *testing.T) {
+	const otsl = "<otsl><ched>Region<ched>Sales<lcel><nl><ched>Name<ched>2024<ched>2025<nl><rhed>North<fcel>1<fcel>2<nl><rhed>South<fcel>3<lcel><nl><ucel><ucel><xcel><nl><srow>Total<ecel><fcel>5<nl></otsl>"
+	table, err := ParseOTSL(otsl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if table.NumRows != 6 || table.NumCols != 3 {
+		t.Fatalf("unexpected table size %dx%d", table.NumRows, table.NumCols)
+	}
+	south := table.Grid()[4][2]
+	if south.Text != "3" || south.RowSpan != 2 || south.ColSpan != 2 || south.StartRow != 3 || south.StartCol != 1 {
+		t.Fatalf("unexpected spanning cell %+v", south)
+	}
+	if south := table.Grid()[4][0]; south.Text != "South" || south.RowSpan != 2 || !south.RowHeader {
+		t.Fatalf("unexpected row header %+v", south)
+	}
+	if n := table.HeaderRows(); n != 2 {
+		t.Fatalf("expected 2 header rows, got %d", n)
+	}
+	if got := table.OTSL(); got != otsl {
+		t.Fatalf("expected %s, got %s", otsl, got)
+	}
+	// tokens without wrapper, as sent by VLM pipelines
+	bare, err := ParseOTSL("<fcel>a<fcel>b<nl><fcel>c<ecel><nl>")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := [][]string{{"a", "b"}, {"c", ""}}; !reflect.DeepEqual(bare.Rows(), want) {
+		t.Fatalf("expected rows %q, got %q", want, bare.Rows())
+	}
+	_, err = ParseOTSL("<lcel>a<nl>")
+	if err == nil {
+		t.Fatal("expected an error for a merge without cell")
+	}
+}
+
+func TestOTSLEscape(t *testing.T) {
+	table := Table{NumRows: 1, NumCols: 2, Cells: []TableCell{
+		{EndRow: 1, EndCol: 1, RowSpan: 1, ColSpan: 1, Text: "a <fcel> b"},
+		{EndRow: 1, StartCol: 1, EndCol: 2, RowSpan: 1, ColSpan: 1, Text: "x &lt; y <nl>"},
+	}}
+	otsl := table.OTSL()
+	if otsl != "<otsl><fcel>a &lt;fcel&gt; b<fcel>x &amp;lt; y &lt;nl&gt;<nl></otsl>" {
+		t.Fatalf("unexpected otsl %s", otsl)
+	}
+	decoded, err := ParseOTSL(otsl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := [][]string{{"a <fcel> b", "x &lt; y <nl>"}}; !reflect.DeepEqual(decoded.Rows(), want) {
+		t.Fatalf("expected rows %q, got %q", want, decoded.Rows())
+	}
+}
@@ -0,0 +1,229 @@
+package docling
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"image"
+	"slices"
+	"strings"
+)
+
+// Page is the content of a page of a converted document. It holds the items
+// of the body content layer only, page headers and footers are left out.
+type Page struct {
+	Number    int
+	Size      Size
+	ImageRef  *ImageRef // set when the pages were converted with IncludeImages
+	ImageData []byte    // decoded page image, when embedded
+	Items     []string  // refs of the items on the page, in reading order
+	Texts     []TextItem
+	Tables    []Table
+	Pictures  []Picture
+	Text      string
+	// Markdown approximates the markdown export of the page, it is rendered
+	// from the items by this package and may differ from the markdown of the
+	// server, e.g. for escaping, lists or tables. For the exact markdown of
+	// each page, convert with WithMDPageBreakPlaceholder and cut the markdown
+	// content with markdown.SplitPages.
+	Markdown string
+}
+
+// Page returns the page n, numbered from 1, of the json content of the
+// document.
+func (d Document) Page(n int) (Page, error) {
+	doc, err := d.DoclingDocument()
+	if err != nil {
+		return Page{}, err
+	}
+	return doc.Page(n)
+}
+
+// Pages returns the pages of the json content of the document in page order.
+func (d Document) Pages() ([]Page, error) {
+	doc, err := d.DoclingDocument()
+	if err != nil {
+		return nil, err
+	}
+	return doc.AllPages()
+}
+
+func (doc *DoclingDocument) AllPages() ([]Page, error) {
+	numbers := make([]int, 0, len(doc.Pages))
+	for n := range doc.Pages {
+		numbers = append(numbers, n)
+	}
+	slices.Sort(numbers)
+	pages := make([]Page, 0, len(numbers))
+	for _, n := range numbers {
+		p, err := doc.Page(n)
+		if err != nil {
+			return nil, err
+		}
+		pages = append(pages, p)
+	}
+	return pages, nil
+}
+
+func (doc *DoclingDocument) Page(n int) (Page, error) {
+	item, ok := doc.Pages[n]
+	if !ok {
+		return Page{}, fmt.Errorf("page %d not found", n)
+	}
+	p := Page{
+		Number:   n,
+		Size:     item.Size,
+		ImageRef: item.Image,
+	}
+	if item.Image != nil {
+		_, data, ok, err := decodeDataURI(item.Image.URI)
+		if ok && err != nil {
+			return Page{}, fmt.Errorf("failed to decode image of page %d: %w", n, err)
+		}
+		p.ImageData = data
+	}
+	var texts, blocks []string
+	var err error
+	doc.walk(doc.Body.Children, func(ref Ref, node any) bool {
+		var item DocItem
+		switch node := node.(type) {
+		case *GroupItem:
+			return true
+		case *TextItem:
+			item = node.DocItem
+		case *TableItem:
+			item = node.DocItem
+		case *PictureItem:
+			item = node.DocItem
+		default:
+			return false
+		}
+		if cmp.Or(item.ContentLayer, ContentLayerBody) != ContentLayerBody || !onPage(item.Prov, n) {
+			// captions and other children follow their parent
+			return false
+		}
+		p.Items = append(p.Items, ref.Ref)
+		switch node := node.(type) {
+		case *TextItem:
+			p.Texts = append(p.Texts, *node)
+			texts = append(texts, node.Text)
+			blocks = append(blocks, textMarkdown(*node))
+		case *TableItem:
+			t := doc.table(*node)
+			p.Tables = append(p.Tables, t)
+			blocks = append(blocks, tableMarkdown(t))
+		case *PictureItem:
+			var pic Picture
+			pic, err = doc.picture(*node)
+			if err != nil {
+				return false
+			}
+			p.Pictures = append(p.Pictures, pic)
+			blocks = append(blocks, "<!-- image -->")
+		}
+		return true
+	})
+	if err != nil {
+		return Page{}, err
+	}
+	p.Text = strings.Join(texts, "\n")
+	p.Markdown = strings.Join(blocks, "\n\n")
+	return p, nil
+}
+
+// Image decodes the embedded page image.
+func (p Page) Image() (image.Image, error) {
+	if p.ImageData == nil {
+		return nil, ErrNoImageData
+	}
+	img, _, err := image.Decode(bytes.NewReader(p.ImageData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image of page %d: %w", p.Number, err)
+	}
+	return img, nil
+}
+
+// walk visits the items the refs point to and their children in reading
+// order, the children of a node are skipped when fn returns false.
+func (doc *DoclingDocument) walk(refs []Ref, fn func(ref Ref, node any) bool) {
+	for _, ref := range refs {
+		array, i, ok := ref.item()
+		if !ok {
+			continue
+		}
+		var (
+			node     any
+			children []Ref
+		)
+		switch {
+		case array == "texts" && i < len(doc.Texts):
+			node, children = &doc.Texts[i], doc.Texts[i].Children
+		case array == "tables" && i < len(doc.Tables):
+			node, children = &doc.Tables[i], doc.Tables[i].Children
+		case array == "pictures" && i < len(doc.Pictures):
+			node, children = &doc.Pictures[i], doc.Pictures[i].Children
+		case array == "groups" && i < len(doc.Groups):
+			node, children = &doc.Groups[i], doc.Groups[i].Children
+		default:
+			continue
+		}
+		if fn(ref, node) {
+			doc.walk(children, fn)
+		}
+	}
+}
+
+func onPage(prov []ProvenanceItem, n int) bool {
+	for _, p := range prov {
+		if p.PageNo == n {
+			return true
+		}
+	}
+	return false
+}
+
+// textMarkdown renders a text item the way docling exports it to markdown.
+func textMarkdown(t TextItem) string {
+	switch t.Label {
+	case "title":
+		return "# " + t.Text
+	case "section_header":
+		return strings.Repeat("#", max(t.Level, 1)+1) + " " + t.Text
+	case "list_item":
+		return cmp.Or(t.Marker, "-") + " " + t.Text
+	case "code":
+		return "```\n" + t.Text + "\n```"
+	case "formula":
+		return "$$" + t.Text + "$$"
+	}
+	return t.Text
+}
+
+// tableMarkdown renders a table as a markdown table, the first row being the
+// header.
+func tableMarkdown(t Table) string {
+	rows := t.Rows()
+	if len(rows) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	writeRow := func(row []string) {
+		b.WriteString("|")
+		for _, cell := range row {
+			b.WriteString(" ")
+			b.WriteString(strings.ReplaceAll(strings.ReplaceAll(cell, "|", `\|`), "\n", " "))
+			b.WriteString(" |")
+		}
+		b.WriteString("\n")
+	}
+	writeRow(rows[0])
+	b.WriteString("|")
+	for range rows[0] {
+		b.WriteString("---|")
+	}
+	b.WriteString("\n")
+	for _, row := range rows[1:] {
+		writeRow(row)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
@@ -0,0 +1,49 @@
+package docling
+
+import (
+	"testing"
+)
+
+func TestPage(t *testing.T) {
+	doc := Document{Contents: []Content{JSONContent(`{
+		"schema_name": "DoclingDocument",
+		"body": {"self_ref": "#/body", "children": [{"$ref": "#/texts/0"}, {"$ref": "#/texts/1"}, {"$ref": "#/groups/0"}, {"$ref": "#/texts/3"}, {"$ref": "#/texts/4"}]},
+		"groups": [{"self_ref": "#/groups/0", "label": "list", "children": [{"$ref": "#/texts/2"}]}],
+		"texts": [
+			{"self_ref": "#/texts/0", "label": "page_header", "content_layer": "furniture", "text": "ACME", "prov": [{"page_no": 1}]},
+			{"self_ref": "#/texts/1", "label": "section_header", "level": 1, "text": "Intro", "prov": [{"page_no": 1}]},
+			{"self_ref": "#/texts/2", "label": "list_item", "marker": "-", "text": "first", "prov": [{"page_no": 1}]},
+			{"self_ref": "#/texts/3", "label": "text", "text": "Next page.", "prov": [{"page_no": 2}]},
+			{"self_ref": "#/texts/4", "label": "text", "text": "Done.", "prov": [{"page_no": 1}]}
+		],
+		"pages": {"1": {"size": {"width": 612, "height": 792}, "page_no": 1}, "2": {"size": {"width": 612, "height": 792}, "page_no": 2}}
+	}`)}}
+	p, err := doc.Page(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Size.Width != 612 || len(p.Items) != 3 || len(p.Texts) != 3 {
+		t.Fatalf("unexpected page %+v", p)
+	}
+	if p.Text != "Intro\nfirst\nDone." {
+		t.Fatalf("unexpected text %q", p.Text)
+	}
+	if want := "## Intro\n\n- first\n\nDone."; p.Markdown != want {
+		t.Fatalf("expected markdown %q, got %q", want, p.Markdown)
+	}
+	pages, err := doc.Pages()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pages) != 2 || pages[1].Text != "Next page." {
+		t.Fatalf("unexpected pages %+v", pages)
+	}
+	_, err = doc.Page(3)
+	if err == nil {
+		t.Fatal("expected an error for a missing page")
+	}
+	_, err = p.Image()
+	if err != ErrNoImageData {
+		t.Fatalf("expected ErrNoImageData, got %v", err)
+	}
+}
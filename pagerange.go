@@ -0,0 +1,90 @@
+package docling
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// PageRange selects the pages to convert, bounds are inclusive and 1-based.
+// A zero From starts at the first page and a zero To goes up to the last page,
+// so the zero value converts the whole document.
+type PageRange struct {
+	From int
+	To   int
+}
+
+func (r PageRange) bounds() (int, int) {
+	from, to := r.From, r.To
+	if from == 0 {
+		from = 1
+	}
+	if to == 0 {
+		to = math.MaxInt64
+	}
+	return from, to
+}
+
+func (r PageRange) String() string {
+	from, to := r.bounds()
+	if to == math.MaxInt64 {
+		return fmt.Sprintf("[%d,]", from)
+	}
+	return fmt.Sprintf("[%d,%d]", from, to)
+}
+
+func (r PageRange) MarshalJSON() ([]byte, error) {
+	from, to := r.bounds()
+	return json.Marshal([2]int{from, to})
+}
+
+func (r *PageRange) UnmarshalJSON(data []byte) error {
+	var bounds []int
+	err := json.Unmarshal(data, &bounds)
+	if err != nil {
+		return err
+	}
+	if len(bounds) != 2 {
+		return fmt.Errorf("page range must contain two values, got %d", len(bounds))
+	}
+	r.From, r.To = bounds[0], bounds[1]
+	if r.To == math.MaxInt64 {
+		r.To = 0
+	}
+	return nil
+}
+
+func (r PageRange) formValues() []string {
+	from, to := r.bounds()
+	return []string{strconv.Itoa(from), strconv.Itoa(to)}
+}
+
+// maxPageRangeShards bounds the number of windows of a split range.
+const maxPageRangeShards = 1 << 16
+
+// Split cuts the range in consecutive windows of at most size pages. The range
+// must have an upper bound since the page count of a document is not known.
+func (r PageRange) Split(size int) ([]PageRange, error) {
+	if size <= 0 {
+		return nil, errors.New("shard size must be positive")
+	}
+	if r.To == 0 || r.To == math.MaxInt64 {
+		return nil, errors.New("page range must have an upper bound to be split")
+	}
+	from, to := r.bounds()
+	if from < 1 || to < from {
+		return nil, fmt.Errorf("invalid page range %s", r)
+	}
+	n := (to-from)/size + 1
+	if n > maxPageRangeShards {
+		return nil, fmt.Errorf("page range %s splits in more than %d shards", r, maxPageRangeShards)
+	}
+	shards := make([]PageRange, 0, n)
+	for i := range n {
+		start := from + i*size
+		shards = append(shards, PageRange{From: start, To: start + min(size-1, to-start)})
+	}
+	return shards, nil
+}
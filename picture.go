@@ -0,0 +1,141 @@
+package docling
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"net/url"
+	"strings"
+)
+
+var ErrNoImageData = errors.New("picture has no embedded image")
+
+// Picture is a picture of a converted document with its image decoded and its
+// annotations resolved. Data is only set when the images were exported in
+// embedded mode, URI holds the reference of the image otherwise.
+type Picture struct {
+	Ref         string
+	Page        int          // page of the first provenance, 0 when unknown
+	BBox        *BoundingBox // bounding box of the first provenance
+	Prov        []ProvenanceItem
+	Caption     string
+	Footnotes   []string
+	MimeType    string
+	Data        []byte
+	URI         string
+	Size        Size // size of the image in pixels
+	DPI         int
+	Classes     []PictureClass // in the order of the server, highest confidence first
+	Description string
+}
+
+// Pictures returns the pictures of the json content of the document.
+func (d Document) Pictures() ([]Picture, error) {
+	doc, err := d.DoclingDocument()
+	if err != nil {
+		return nil, err
+	}
+	return doc.AllPictures()
+}
+
+// AllPictures returns the pictures of the document in document order.
+func (doc *DoclingDocument) AllPictures() ([]Picture, error) {
+	pictures := make([]Picture, 0, len(doc.Pictures))
+	for _, item := range doc.Pictures {
+		p, err := doc.picture(item)
+		if err != nil {
+			return nil, err
+		}
+		pictures = append(pictures, p)
+	}
+	return pictures, nil
+}
+
+func (doc *DoclingDocument) picture(item PictureItem) (Picture, error) {
+	p := Picture{
+		Ref:       item.SelfRef,
+		Prov:      item.Prov,
+		Caption:   strings.Join(doc.texts(item.Captions), " "),
+		Footnotes: doc.texts(item.Footnotes),
+	}
+	if len(item.Prov) > 0 {
+		p.Page = item.Prov[0].PageNo
+		p.BBox = &item.Prov[0].BBox
+	}
+	if img := item.Image; img != nil {
+		p.MimeType = img.Mimetype
+		p.URI = img.URI
+		p.Size = img.Size
+		p.DPI = img.DPI
+		if mimeType, data, ok, err := decodeDataURI(img.URI); ok {
+			if err != nil {
+				return Picture{}, fmt.Errorf("failed to decode image of %s: %w", item.SelfRef, err)
+			}
+			p.Data = data
+			p.MimeType = cmp.Or(p.MimeType, mimeType)
+			p.URI = ""
+		}
+	}
+	for _, a := range item.Annotations {
+		switch a.Kind {
+		case "classification":
+			p.Classes = append(p.Classes, a.PredictedClasses...)
+		case "description":
+			p.Description = a.Text
+		}
+	}
+	if m := item.Meta; m != nil {
+		if m.Classification != nil && len(p.Classes) == 0 {
+			p.Classes = m.Classification.Predictions
+		}
+		if m.Description != nil && p.Description == "" {
+			p.Description = m.Description.Text
+		}
+	}
+	return p, nil
+}
+
+// Image decodes the embedded image, PNG, JPEG and GIF are supported.
+func (p Picture) Image() (image.Image, error) {
+	if p.Data == nil {
+		return nil, ErrNoImageData
+	}
+	img, _, err := image.Decode(bytes.NewReader(p.Data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image of %s: %w", p.Ref, err)
+	}
+	return img, nil
+}
+
+// decodeDataURI decodes a data URI, ok is false when uri is not one.
+func decodeDataURI(uri string) (mimeType string, data []byte, ok bool, err error) {
+	rest, ok := strings.CutPrefix(uri, "data:")
+	if !ok {
+		return "", nil, false, nil
+	}
+	meta, payload, found := strings.Cut(rest, ",")
+	if !found {
+		return "", nil, true, errors.New("malformed data URI")
+	}
+	mimeType, isBase64 := strings.CutSuffix(meta, ";base64")
+	if i := strings.IndexByte(mimeType, ';'); i >= 0 {
+		mimeType = mimeType[:i]
+	}
+	if isBase64 {
+		data, err = base64.StdEncoding.DecodeString(payload)
+	} else {
+		var s string
+		s, err = url.PathUnescape(payload)
+		data = []byte(s)
+	}
+	if err != nil {
+		return "", nil, true, err
+	}
+	return mimeType, data, true, nil
+}
@@ -0,0 +1,71 @@
+package docling
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/png"
+	"testing"
+)
+
+func TestPictures(t *testing.T) {
+	var b bytes.Buffer
+	err := png.Encode(&b, image.NewGray(image.Rect(0, 0, 4, 3)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	uri := "data:image/png;base64," + base64.StdEncoding.EncodeToString(b.Bytes())
+	doc := Document{Contents: []Content{JSONContent(fmt.Sprintf(`{
+		"schema_name": "DoclingDocument",
+		"texts": [{"self_ref": "#/texts/0", "label": "caption", "text": "Figure 1: Architecture"}],
+		"pictures": [
+			{
+				"self_ref": "#/pictures/0",
+				"label": "picture",
+				"captions": [{"$ref": "#/texts/0"}],
+				"prov": [{"page_no": 3, "bbox": {"l": 1, "t": 2, "r": 3, "b": 4, "coord_origin": "BOTTOMLEFT"}, "charspan": [0, 0]}],
+				"image": {"mimetype": "image/png", "dpi": 144, "size": {"width": 4, "height": 3}, "uri": %q},
+				"annotations": [
+					{"kind": "classification", "provenance": "DocumentFigureClassifier", "predicted_classes": [{"class_name": "flow_chart", "confidence": 0.9}, {"class_name": "bar_chart", "confidence": 0.1}]},
+					{"kind": "description", "provenance": "SmolVLM", "text": "A diagram."}
+				]
+			},
+			{
+				"self_ref": "#/pictures/1",
+				"label": "picture",
+				"image": {"mimetype": "image/png", "dpi": 72, "size": {"width": 10, "height": 10}, "uri": "artifacts/image_000001.png"},
+				"meta": {"description": {"text": "A logo."}}
+			}
+		]
+	}`, uri))}}
+	pictures, err := doc.Pictures()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pictures) != 2 {
+		t.Fatalf("expected 2 pictures, got %d", len(pictures))
+	}
+	p := pictures[0]
+	if p.Page != 3 || p.BBox.R != 3 || p.Caption != "Figure 1: Architecture" || p.DPI != 144 || p.MimeType != "image/png" {
+		t.Fatalf("unexpected picture %+v", p)
+	}
+	if len(p.Classes) != 2 || p.Classes[0].ClassName != "flow_chart" || p.Description != "A diagram." {
+		t.Fatalf("unexpected annotations %+v %q", p.Classes, p.Description)
+	}
+	img, err := p.Image()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size := img.Bounds().Size(); size.X != 4 || size.Y != 3 {
+		t.Fatalf("unexpected image size %v", size)
+	}
+	p = pictures[1]
+	if p.Data != nil || p.URI != "artifacts/image_000001.png" || p.Description != "A logo." {
+		t.Fatalf("unexpected referenced picture %+v", p)
+	}
+	_, err = p.Image()
+	if err != ErrNoImageData {
+		t.Fatalf("expected ErrNoImageData, got %v", err)
+	}
+}
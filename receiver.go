@@ -0,0 +1,335 @@
+package docling
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// PutResult is a conversion result uploaded by docling-serve to a TargetPut
+// URL. A zip upload holds one response per converted document.
+type PutResult struct {
+	Key       string
+	Responses []ConvertResponse
+	Err       error
+}
+
+// PutReceiver is an http.Handler receiving the results docling-serve uploads
+// to TargetPut URLs built by Target. The last path element of the request is
+// the key of the result, so the handler can be mounted under any prefix.
+//
+// Uploads must carry Token, as the token query parameter set by Target or as
+// a bearer token: a receiver without Token rejects every upload unless
+// Insecure is set, since anyone able to reach it could otherwise make it hold
+// results in memory. Results nobody waits for yet are kept for PendingTTL, up
+// to MaxPending of them.
+type PutReceiver struct {
+	Token      string          // required unless Insecure is set
+	Insecure   bool            // accept uploads without token, only for receivers on a trusted network
+	MaxSize    int64           // default: 100MB, bounds the upload and the decompressed size of a zip result
+	MaxPending int             // default: 100, the oldest pending result is dropped above it
+	PendingTTL time.Duration   // default: 10m
+	OnResult   func(PutResult) // optional: called for every result, Wait does not see results when set
+
+	mu      sync.Mutex
+	waiters map[string][]chan PutResult
+	pending map[string]pendingResult
+}
+
+type pendingResult struct {
+	PutResult
+	received time.Time
+}
+
+// Target returns the TargetPut sending the result of a conversion to the
+// receiver served at baseURL, under the given key.
+func (pr *PutReceiver) Target(baseURL, key string) (TargetPut, error) {
+	if key == "" || strings.Contains(key, "/") {
+		return TargetPut{}, fmt.Errorf("invalid result key %q", key)
+	}
+	if pr.Token == "" && !pr.Insecure {
+		return TargetPut{}, errors.New("put receiver has no token")
+	}
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return TargetPut{}, fmt.Errorf("failed to parse base URL: %w", err)
+	}
+	u = u.JoinPath(key)
+	if pr.Token != "" {
+		q := u.Query()
+		q.Set("token", pr.Token)
+		u.RawQuery = q.Encode()
+	}
+	return TargetPut{URL: u.String()}, nil
+}
+
+// Wait returns the result uploaded under key, waiting for it if needed.
+func (pr *PutReceiver) Wait(ctx context.Context, key string) (PutResult, error) {
+	pr.mu.Lock()
+	pr.expire(time.Now())
+	if res, ok := pr.pending[key]; ok {
+		delete(pr.pending, key)
+		pr.mu.Unlock()
+		return res.PutResult, nil
+	}
+	if pr.waiters == nil {
+		pr.waiters = make(map[string][]chan PutResult)
+	}
+	ch := make(chan PutResult, 1)
+	pr.waiters[key] = append(pr.waiters[key], ch)
+	pr.mu.Unlock()
+	select {
+	case res := <-ch:
+		return res, nil
+	case <-ctx.Done():
+		pr.mu.Lock()
+		defer pr.mu.Unlock()
+		waiters := slices.DeleteFunc(pr.waiters[key], func(c chan PutResult) bool {
+			return c == ch
+		})
+		if len(waiters) == 0 {
+			delete(pr.waiters, key)
+		} else {
+			pr.waiters[key] = waiters
+		}
+		// the result may have been delivered along with the cancellation
+		select {
+		case res := <-ch:
+			return res, nil
+		default:
+		}
+		return PutResult{}, ctx.Err()
+	}
+}
+
+func (pr *PutReceiver) deliver(res PutResult) {
+	if pr.OnResult != nil {
+		pr.OnResult(res)
+		return
+	}
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	if waiters, ok := pr.waiters[res.Key]; ok {
+		delete(pr.waiters, res.Key)
+		for _, ch := range waiters {
+			ch <- res
+		}
+		return
+	}
+	now := time.Now()
+	pr.expire(now)
+	if pr.pending == nil {
+		pr.pending = make(map[string]pendingResult)
+	}
+	maxPending := pr.MaxPending
+	if maxPending <= 0 {
+		maxPending = 100
+	}
+	for len(pr.pending) >= maxPending {
+		var oldest string
+		for key, p := range pr.pending {
+			if oldest == "" || p.received.Before(pr.pending[oldest].received) {
+				oldest = key
+			}
+		}
+		delete(pr.pending, oldest)
+	}
+	pr.pending[res.Key] = pendingResult{PutResult: res, received: now}
+}
+
+// expire drops the pending results older than PendingTTL.
+func (pr *PutReceiver) expire(now time.Time) {
+	ttl := pr.PendingTTL
+	if ttl <= 0 {
+		ttl = 10 * time.Minute
+	}
+	for key, p := range pr.pending {
+		if now.Sub(p.received) > ttl {
+			delete(pr.pending, key)
+		}
+	}
+}
+
+func (pr *PutReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		w.Header().Set("Allow", http.MethodPut)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !pr.authorized(r) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	key := path.Base(r.URL.Path)
+	if key == "" || key == "/" || key == "." {
+		http.Error(w, "missing result key", http.StatusNotFound)
+		return
+	}
+	maxSize := pr.MaxSize
+	if maxSize <= 0 {
+		maxSize = 100 << 20
+	}
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxSize))
+	if err != nil {
+		var mbe *http.MaxBytesError
+		if errors.As(err, &mbe) {
+			http.Error(w, "result too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, "failed to read result", http.StatusBadRequest)
+		return
+	}
+	resps, err := decodeResult(data, maxSize)
+	pr.deliver(PutResult{Key: key, Responses: resps, Err: err})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (pr *PutReceiver) authorized(r *http.Request) bool {
+	if pr.Token == "" {
+		return pr.Insecure
+	}
+	token := r.URL.Query().Get("token")
+	if auth, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		token = auth
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(pr.Token)) == 1
+}
+
+// zipContentFormats maps the extension of the files of a zip result to their
+// format, the longest extensions come first.
+var zipContentFormats = []struct {
+	ext    string
+	format ToFormat
+}{
+	{".doctags.txt", ToDocTags},
+	{".doctags", ToDocTags},
+	{".txt", ToText},
+	{".split_page.html", ToHTMLSplitPage},
+	{".md", ToMarkdown},
+	{".json", ToJSON},
+	{".html", ToHTML},
+}
+
+// DecodeResult decodes a conversion result as produced for the put and zip
+// targets: either a zip archive of exported files or a JSON response. The
+// decompressed size of a zip archive is not bounded, data must come from a
+// trusted source.
+func DecodeResult(data []byte) ([]ConvertResponse, error) {
+	return decodeResult(data, -1)
+}
+
+// decodeResult decodes a result, failing when the files of a zip archive
+// decompress to more than maxSize bytes in total, unless maxSize is negative.
+func decodeResult(data []byte, maxSize int64) ([]ConvertResponse, error) {
+	if bytes.HasPrefix(data, []byte("PK\x03\x04")) {
+		return decodeZipResult(data, maxSize)
+	}
+	var probe map[string]json.RawMessage
+	err := json.Unmarshal(data, &probe)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode result: %w", err)
+	}
+	if _, ok := probe["document"]; ok {
+		var resp ConvertResponse
+		err = json.Unmarshal(data, &resp)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode result: %w", err)
+		}
+		return []ConvertResponse{resp}, nil
+	}
+	if _, ok := probe["schema_name"]; ok {
+		// a bare DoclingDocument
+		var name string
+		json.Unmarshal(probe["name"], &name)
+		return []ConvertResponse{{
+			Status: "success",
+			Document: Document{
+				Filename: name,
+				Contents: []Content{JSONContent(data)},
+			},
+		}}, nil
+	}
+	var doc Document
+	err = json.Unmarshal(data, &doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode result: %w", err)
+	}
+	return []ConvertResponse{{Status: "success", Document: doc}}, nil
+}
+
+func decodeZipResult(data []byte, maxSize int64) ([]ConvertResponse, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open zip result: %w", err)
+	}
+	var (
+		resps []ConvertResponse
+		index = make(map[string]int)
+	)
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		name := path.Base(f.Name)
+		for _, cf := range zipContentFormats {
+			stem, ok := strings.CutSuffix(name, cf.ext)
+			if !ok {
+				continue
+			}
+			rc, err := f.Open()
+			if err != nil {
+				return nil, fmt.Errorf("failed to open %s: %w", f.Name, err)
+			}
+			var r io.Reader = rc
+			if maxSize >= 0 {
+				// the sizes in the zip headers are not trusted
+				r = io.LimitReader(rc, maxSize+1)
+			}
+			content, err := io.ReadAll(r)
+			rc.Close()
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s: %w", f.Name, err)
+			}
+			if maxSize >= 0 {
+				maxSize -= int64(len(content))
+				if maxSize < 0 {
+					return nil, errors.New("zip result is too large once decompressed")
+				}
+			}
+			i, ok := index[stem]
+			if !ok {
+				i = len(resps)
+				index[stem] = i
+				resps = append(resps, ConvertResponse{
+					Status:   "success",
+					Document: Document{Filename: stem},
+				})
+			}
+			resps[i].Document.Contents = append(resps[i].Document.Contents, newContent(cf.format, content))
+			break
+		}
+	}
+	if len(resps) == 0 {
+		return nil, errors.New("zip result holds no converted document")
+	}
+	for _, resp := range resps {
+		sortContents(resp.Document.Contents)
+	}
+	return resps, nil
+}
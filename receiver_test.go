@@ -0,0 +1,166 @@
+package docling
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPutReceiver(t *testing.T) {
+	recv := &PutReceiver{Token: "secret"}
+	srv := httptest.NewServer(http.StripPrefix("/results", recv))
+	defer srv.Close()
+	var b bytes.Buffer
+	zw := zip.NewWriter(&b)
+	for name, content := range map[string]string{"doc.md": "# Title", "doc.json": `{"name":"doc"}`, "artifacts/image_000000.png": "png"} {
+		f, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Write([]byte(content))
+	}
+	zw.Close()
+	target, err := recv.Target(srv.URL+"/results", "task-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	done := make(chan PutResult)
+	go func() {
+		res, err := recv.Wait(ctx, "task-1")
+		if err != nil {
+			t.Error(err)
+		}
+		done <- res
+	}()
+	for url, status := range map[string]int{srv.URL + "/results/task-1": http.StatusUnauthorized, target.URL: http.StatusOK} {
+		req, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(b.Bytes()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != status {
+			t.Fatalf("%s: expected status %d, got %d", url, status, resp.StatusCode)
+		}
+	}
+	res := <-done
+	if res.Err != nil {
+		t.Fatal(res.Err)
+	}
+	if len(res.Responses) != 1 {
+		t.Fatalf("expected 1 response, got %d", len(res.Responses))
+	}
+	doc := res.Responses[0].Document
+	if doc.Filename != "doc" || doc.MarkdownContent() != "# Title" || doc.JSONContent() != `{"name":"doc"}` {
+		t.Fatalf("unexpected document %+v", doc)
+	}
+}
+
+func TestPutReceiverWaiters(t *testing.T) {
+	recv := &PutReceiver{Token: "secret", MaxPending: 2}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	done := make(chan PutResult)
+	go func() {
+		res, err := recv.Wait(context.Background(), "task-1")
+		if err != nil {
+			t.Error(err)
+		}
+		done <- res
+	}()
+	for {
+		recv.mu.Lock()
+		n := len(recv.waiters["task-1"])
+		recv.mu.Unlock()
+		if n == 1 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	// a cancelled waiter leaves the other one waiting
+	_, err := recv.Wait(ctx, "task-1")
+	if err == nil {
+		t.Fatal("expected a cancelled wait to fail")
+	}
+	recv.deliver(PutResult{Key: "task-1"})
+	if res := <-done; res.Key != "task-1" {
+		t.Fatalf("unexpected result %+v", res)
+	}
+	// results nobody waits for are capped
+	for _, key := range []string{"a", "b", "c"} {
+		recv.deliver(PutResult{Key: key})
+	}
+	if _, err := recv.Wait(ctx, "a"); err == nil {
+		t.Fatal("expected the oldest pending result to be dropped")
+	}
+	if res, err := recv.Wait(ctx, "c"); err != nil || res.Key != "c" {
+		t.Fatalf("unexpected result %+v, %v", res, err)
+	}
+}
+
+func TestPutReceiverRequiresToken(t *testing.T) {
+	recv := &PutReceiver{}
+	if _, err := recv.Target("http://127.0.0.1/results", "task-1"); err == nil {
+		t.Fatal("expected an error building a target without token")
+	}
+	rec := httptest.NewRecorder()
+	recv.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/results/task-1", strings.NewReader("{}")))
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected status %d, got %d", http.StatusUnauthorized, rec.Code)
+	}
+}
+
+func TestPutReceiverZipBomb(t *testing.T) {
+	zipOf := func(sizes ...int) []byte {
+		var b bytes.Buffer
+		zw := zip.NewWriter(&b)
+		for i, size := range sizes {
+			f, err := zw.Create(fmt.Sprintf("doc%d.md", i))
+			if err != nil {
+				t.Fatal(err)
+			}
+			f.Write(make([]byte, size))
+		}
+		zw.Close()
+		return b.Bytes()
+	}
+	recv := &PutReceiver{Insecure: true, MaxSize: 64 << 10, OnResult: func(PutResult) {}}
+	srv := httptest.NewServer(recv)
+	defer srv.Close()
+	for _, tc := range []struct {
+		sizes  []int
+		status int
+	}{
+		{[]int{16 << 10, 16 << 10}, http.StatusOK},
+		{[]int{1 << 20}, http.StatusBadRequest},
+		{[]int{40 << 10, 40 << 10}, http.StatusBadRequest},
+	} {
+		body := zipOf(tc.sizes...)
+		if len(body) >= 64<<10 {
+			t.Fatalf("expected a compressed body under the limit, got %d bytes", len(body))
+		}
+		req, err := http.NewRequest(http.MethodPut, srv.URL+"/task-1", bytes.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != tc.status {
+			t.Fatalf("%v: expected status %d, got %d", tc.sizes, tc.status, resp.StatusCode)
+		}
+	}
+}
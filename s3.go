@@ -0,0 +1,429 @@
+package docling
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+type S3Credentials struct {
+	AccessKey    string
+	SecretKey    string
+	SessionToken string
+	Region       string
+}
+
+// LoadS3Credentials resolves credentials the way AWS tools do: the
+// AWS_ACCESS_KEY_ID / AWS_SECRET_ACCESS_KEY environment variables first, then
+// the profile of the shared credentials file. An empty profile uses
+// AWS_PROFILE or "default".
+func LoadS3Credentials(profile string) (S3Credentials, error) {
+	creds := S3Credentials{
+		AccessKey:    os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretKey:    os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken: os.Getenv("AWS_SESSION_TOKEN"),
+		Region:       cmp.Or(os.Getenv("AWS_REGION"), os.Getenv("AWS_DEFAULT_REGION")),
+	}
+	if profile == "" {
+		profile = cmp.Or(os.Getenv("AWS_PROFILE"), "default")
+	}
+	if creds.AccessKey == "" || creds.SecretKey == "" {
+		path, err := awsConfigPath("AWS_SHARED_CREDENTIALS_FILE", "credentials")
+		if err != nil {
+			return S3Credentials{}, err
+		}
+		section, err := readINISection(path, profile)
+		if err != nil {
+			return S3Credentials{}, fmt.Errorf("failed to read credentials of profile %q: %w", profile, err)
+		}
+		creds.AccessKey = section["aws_access_key_id"]
+		creds.SecretKey = section["aws_secret_access_key"]
+		creds.SessionToken = section["aws_session_token"]
+		if creds.Region == "" {
+			creds.Region = section["region"]
+		}
+	}
+	if creds.AccessKey == "" || creds.SecretKey == "" {
+		return S3Credentials{}, errors.New("no S3 credentials found in the environment nor in the shared credentials file")
+	}
+	if creds.Region == "" {
+		path, err := awsConfigPath("AWS_CONFIG_FILE", "config")
+		if err == nil {
+			name := "profile " + profile
+			if profile == "default" {
+				name = profile
+			}
+			if section, err := readINISection(path, name); err == nil {
+				creds.Region = section["region"]
+			}
+		}
+	}
+	if creds.Region == "" {
+		creds.Region = "us-east-1"
+	}
+	return creds, nil
+}
+
+func awsConfigPath(env, name string) (string, error) {
+	if path := os.Getenv(env); path != "" {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".aws", name), nil
+}
+
+func readINISection(path, name string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var (
+		section map[string]string
+		inside  bool
+	)
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			inside = strings.TrimSpace(line[1:len(line)-1]) == name
+			if inside && section == nil {
+				section = make(map[string]string)
+			}
+			continue
+		}
+		if !inside {
+			continue
+		}
+		k, v, ok := strings.Cut(line, "=")
+		if ok {
+			section[strings.TrimSpace(k)] = strings.TrimSpace(v)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if section == nil {
+		return nil, fmt.Errorf("section %q not found in %s", name, path)
+	}
+	return section, nil
+}
+
+// S3Bucket addresses a bucket prefix. Endpoint is a host, optionally prefixed
+// by a scheme ("http://localhost:9000" for a local stand-in), https is used by
+// default.
+type S3Bucket struct {
+	Endpoint    string
+	Bucket      string
+	KeyPrefix   string
+	PathStyle   bool  // address the bucket in the path rather than in the host
+	VerifySSL   *bool // default: true
+	Credentials S3Credentials
+	HTTPClient  *http.Client // used to list the bucket, default: http.DefaultClient
+
+	now func() time.Time
+}
+
+var bucketNameRegexp = regexp.MustCompile(`^[a-z0-9][a-z0-9.-]{1,61}[a-z0-9]$`)
+
+func (b S3Bucket) Validate() error {
+	var errs []error
+	if b.Endpoint == "" {
+		errs = append(errs, FieldError{Field: "endpoint", Reason: "is required"})
+	}
+	if !bucketNameRegexp.MatchString(b.Bucket) || strings.Contains(b.Bucket, "..") || net.ParseIP(b.Bucket) != nil {
+		errs = append(errs, FieldError{Field: "bucket", Reason: fmt.Sprintf("%q is not a valid bucket name", b.Bucket)})
+	}
+	if strings.HasPrefix(b.KeyPrefix, "/") {
+		errs = append(errs, FieldError{Field: "key_prefix", Reason: "must not start with a slash"})
+	}
+	if len(b.KeyPrefix) > 1024 || !utf8.ValidString(b.KeyPrefix) {
+		errs = append(errs, FieldError{Field: "key_prefix", Reason: "must be valid UTF-8 of at most 1024 bytes"})
+	}
+	if b.Credentials.AccessKey == "" || b.Credentials.SecretKey == "" {
+		errs = append(errs, FieldError{Field: "credentials", Reason: "access and secret keys are required"})
+	}
+	if scheme, _ := b.endpoint(); scheme == "http" && b.VerifySSL != nil && *b.VerifySSL {
+		errs = append(errs, FieldError{Field: "verify_ssl", Reason: "cannot be set for an http endpoint"})
+	}
+	return errors.Join(errs...)
+}
+
+func (b S3Bucket) endpoint() (scheme, host string) {
+	scheme, host, ok := strings.Cut(b.Endpoint, "://")
+	if !ok {
+		return "https", b.Endpoint
+	}
+	return scheme, host
+}
+
+// Source returns a source reading the bucket prefix with the credentials of
+// the bucket, which are sent to docling-serve. docling-serve connects over
+// TLS unless VerifySSL is false, which is set for http endpoints. Session
+// credentials cannot be sent, use PresignedSource with them.
+func (b S3Bucket) Source() (SourceS3, error) {
+	err := b.Validate()
+	if err != nil {
+		return SourceS3{}, err
+	}
+	if b.Credentials.SessionToken != "" {
+		return SourceS3{}, FieldError{Field: "credentials", Reason: "session tokens cannot be sent to docling-serve, use presigned URLs instead"}
+	}
+	scheme, host := b.endpoint()
+	verifySSL := b.VerifySSL
+	if scheme == "http" {
+		verifySSL = Ptr(false)
+	}
+	return SourceS3{
+		Endpoint:  host,
+		VerifySSL: verifySSL,
+		AccessKey: b.Credentials.AccessKey,
+		SecretKey: b.Credentials.SecretKey,
+		Bucket:    b.Bucket,
+		KeyPrefix: b.KeyPrefix,
+	}, nil
+}
+
+// Target returns a target writing to the bucket prefix with the credentials
+// of the bucket, which are sent to docling-serve, as Source does.
+func (b S3Bucket) Target() (TargetS3, error) {
+	src, err := b.Source()
+	if err != nil {
+		return TargetS3{}, err
+	}
+	return TargetS3(src), nil
+}
+
+// PresignedSource returns an HTTP source for the object key (relative to the
+// key prefix) that docling-serve can download without any credential.
+func (b S3Bucket) PresignedSource(key string, expires time.Duration) (SourceHTTP, error) {
+	u, err := b.Presign(http.MethodGet, key, nil, expires)
+	if err != nil {
+		return SourceHTTP{}, err
+	}
+	return SourceHTTP{URL: u}, nil
+}
+
+// PresignedTarget returns a target uploading the result to the object key
+// (relative to the key prefix) without any credential.
+func (b S3Bucket) PresignedTarget(key string, expires time.Duration) (TargetPut, error) {
+	u, err := b.Presign(http.MethodPut, key, nil, expires)
+	if err != nil {
+		return TargetPut{}, err
+	}
+	return TargetPut{URL: u}, nil
+}
+
+// PresignedSources lists the objects under the key prefix and returns a
+// presigned HTTP source for each of them.
+func (b S3Bucket) PresignedSources(ctx context.Context, expires time.Duration) ([]Source, error) {
+	keys, err := b.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	srcs := make([]Source, len(keys))
+	for i, key := range keys {
+		u, err := b.Presign(http.MethodGet, strings.TrimPrefix(key, b.KeyPrefix), nil, expires)
+		if err != nil {
+			return nil, err
+		}
+		srcs[i] = SourceHTTP{URL: u}
+	}
+	return srcs, nil
+}
+
+type listBucketResult struct {
+	Contents []struct {
+		Key string `xml:"Key"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+// List returns the keys of the objects under the key prefix.
+func (b S3Bucket) List(ctx context.Context) ([]string, error) {
+	err := b.Validate()
+	if err != nil {
+		return nil, err
+	}
+	httpCli := b.HTTPClient
+	if httpCli == nil {
+		httpCli = http.DefaultClient
+	}
+	var keys []string
+	var token string
+	for {
+		query := url.Values{
+			"list-type": {"2"},
+			"prefix":    {b.KeyPrefix},
+		}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+		u, err := b.presign(http.MethodGet, "", query, 5*time.Minute)
+		if err != nil {
+			return nil, err
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := httpCli.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list bucket: %w", err)
+		}
+		var result listBucketResult
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("failed to list bucket: unexpected status code: %d", resp.StatusCode)
+		}
+		err = xml.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode bucket listing: %w", err)
+		}
+		for _, c := range result.Contents {
+			// skip "directory" placeholders
+			if !strings.HasSuffix(c.Key, "/") {
+				keys = append(keys, c.Key)
+			}
+		}
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return keys, nil
+		}
+		token = result.NextContinuationToken
+	}
+}
+
+// Presign returns a URL allowing method on the object key (relative to the
+// key prefix) for the given duration, signed with AWS signature version 4.
+func (b S3Bucket) Presign(method, key string, query url.Values, expires time.Duration) (string, error) {
+	err := b.Validate()
+	if err != nil {
+		return "", err
+	}
+	if key == "" {
+		return "", errors.New("object key is required")
+	}
+	return b.presign(method, b.KeyPrefix+key, query, expires)
+}
+
+func (b S3Bucket) presign(method, key string, query url.Values, expires time.Duration) (string, error) {
+	if expires <= 0 || expires > 7*24*time.Hour {
+		return "", fmt.Errorf("presigned URL expiration must be between 1s and 7 days, got %s", expires)
+	}
+	now := time.Now
+	if b.now != nil {
+		now = b.now
+	}
+	t := now().UTC()
+	date := t.Format("20060102")
+	region := cmp.Or(b.Credentials.Region, "us-east-1")
+	scope := fmt.Sprintf("%s/%s/s3/aws4_request", date, region)
+	scheme, host := b.endpoint()
+	path := "/" + key
+	if b.PathStyle {
+		path = "/" + b.Bucket + path
+	} else {
+		host = b.Bucket + "." + host
+	}
+	q := url.Values{}
+	for k, v := range query {
+		q[k] = v
+	}
+	q.Set("X-Amz-Algorithm", "AWS4-HMAC-SHA256")
+	q.Set("X-Amz-Credential", b.Credentials.AccessKey+"/"+scope)
+	q.Set("X-Amz-Date", t.Format("20060102T150405Z"))
+	q.Set("X-Amz-Expires", strconv.Itoa(int(expires.Seconds())))
+	q.Set("X-Amz-SignedHeaders", "host")
+	if b.Credentials.SessionToken != "" {
+		q.Set("X-Amz-Security-Token", b.Credentials.SessionToken)
+	}
+	canonicalURI := awsEscape(path, false)
+	canonicalQuery := awsCanonicalQuery(q)
+	canonicalRequest := strings.Join([]string{
+		method,
+		canonicalURI,
+		canonicalQuery,
+		"host:" + host + "\n",
+		"host",
+		"UNSIGNED-PAYLOAD",
+	}, "\n")
+	hash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		t.Format("20060102T150405Z"),
+		scope,
+		hex.EncodeToString(hash[:]),
+	}, "\n")
+	signingKey := hmacSHA256([]byte("AWS4"+b.Credentials.SecretKey), date)
+	signingKey = hmacSHA256(signingKey, region)
+	signingKey = hmacSHA256(signingKey, "s3")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+	return fmt.Sprintf("%s://%s%s?%s&X-Amz-Signature=%s", scheme, host, canonicalURI, canonicalQuery, signature), nil
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+func awsCanonicalQuery(q url.Values) string {
+	keys := make([]string, 0, len(q))
+	for k := range q {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var parts []string
+	for _, k := range keys {
+		values := append([]string(nil), q[k]...)
+		sort.Strings(values)
+		for _, v := range values {
+			parts = append(parts, awsEscape(k, true)+"="+awsEscape(v, true))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// awsEscape percent-encodes everything but the unreserved characters, and
+// the slashes of paths.
+func awsEscape(s string, encodeSlash bool) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '_', c == '.', c == '~':
+			sb.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			sb.WriteByte(c)
+		default:
+			fmt.Fprintf(&sb, "%%%02X", c)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,228 @@
+package docling
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+type ShardOptions struct {
+	ShardSize    int           // required: number of pages per shard
+	Concurrency  int           // default: the WithMaxAsyncInFlight limit, or all shards at once
+	MaxRetries   int           // default: 0, number of retries of a failed shard
+	PollInterval time.Duration // default: 1s
+}
+
+// ProcessFileSharded converts the single file of the request in consecutive
+// page windows submitted as async tasks, and merges the results back in page
+// order. The request page range must have an upper bound. Failed shards are
+// retried on their own, up to MaxRetries times.
+func (c *Client) ProcessFileSharded(ctx context.Context, req ProcessFileRequest, opts ShardOptions) (ConvertResponse, error) {
+	if len(req.Files) != 1 {
+		return ConvertResponse{}, fmt.Errorf("sharded conversion requires exactly one file, got %d", len(req.Files))
+	}
+	shards, err := req.PageRange.Split(opts.ShardSize)
+	if err != nil {
+		return ConvertResponse{}, err
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = time.Second
+	}
+	// shards are submitted and polled concurrently, a shard holds a slot from
+	// submission to result so that no more tasks than the limit are queued on
+	// the server
+	concurrency := opts.Concurrency
+	if concurrency <= 0 && c.asyncLimiter != nil {
+		concurrency = c.asyncLimiter.max
+	}
+	if concurrency <= 0 {
+		concurrency = len(shards)
+	}
+	f := req.Files[0]
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return ConvertResponse{}, fmt.Errorf("failed to read file %q: %w", f.Name(), err)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	resps := make([]ConvertResponse, len(shards))
+	// the merged document is built in memory, the spooled contents of the
+	// shards are removed once merged or on failure
+	defer func() {
+		for _, resp := range resps {
+			resp.Document.Close()
+		}
+	}()
+	errs := make([]error, len(shards))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, shard := range shards {
+		wg.Go(func() {
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				errs[i] = ctx.Err()
+				return
+			}
+			shardReq := req
+			shardReq.PageRange = shard
+			for attempt := 0; ; attempt++ {
+				shardReq.Files = []File{FileReader{Filename: f.Name(), Reader: bytes.NewReader(data)}}
+				resps[i], errs[i] = c.convertShard(ctx, shardReq, opts.PollInterval)
+				if errs[i] == nil || ctx.Err() != nil || attempt >= opts.MaxRetries {
+					break
+				}
+				c.logger.WarnContext(ctx, "retrying failed shard", slog.String("pages", shard.String()), slog.Int("attempt", attempt+1), slog.Any("error", errs[i]))
+			}
+			if errs[i] != nil {
+				errs[i] = fmt.Errorf("failed to convert pages %s: %w", shard, errs[i])
+				cancel()
+			}
+		})
+	}
+	wg.Wait()
+	err = errors.Join(errs...)
+	if err != nil {
+		return ConvertResponse{}, err
+	}
+	merged, err := mergeConvertResponses(resps, shards)
+	if err != nil {
+		return ConvertResponse{}, err
+	}
+	merged.Document.splitPageHTML(req.ToFormats)
+	return merged, nil
+}
+
+func (c *Client) convertShard(ctx context.Context, req ProcessFileRequest, pollInterval time.Duration) (ConvertResponse, error) {
+	task, err := c.ProcessFileAsync(ctx, req)
+	if err != nil {
+		return ConvertResponse{}, err
+	}
+	status, err := c.waitTask(ctx, task.TaskID, pollInterval)
+	if err != nil {
+		return ConvertResponse{}, err
+	}
+	if status.TaskStatus != "success" {
+		return ConvertResponse{}, fmt.Errorf("task %s ended with status %q", task.TaskID, status.TaskStatus)
+	}
+	resp, err := c.GetConvertTaskResult(ctx, task.TaskID)
+	if err != nil {
+		return ConvertResponse{}, err
+	}
+	if resp.Status == "failure" {
+		resp.Document.Close()
+		return ConvertResponse{}, fmt.Errorf("task %s conversion failed: %v", task.TaskID, resp.Errors)
+	}
+	resp.QueueWait += task.QueueWait
+	return resp, nil
+}
+
+// mergeConvertResponses concatenates the responses of consecutive page ranges.
+// Contents of a format which cannot be merged fail the merge rather than being
+// dropped.
+func mergeConvertResponses(resps []ConvertResponse, ranges []PageRange) (ConvertResponse, error) {
+	if len(resps) == 0 {
+		return ConvertResponse{}, nil
+	}
+	merged := ConvertResponse{
+		Status: "success",
+	}
+	merged.Document.Filename = resps[0].Document.Filename
+	var (
+		formats   []ToFormat // in order of appearance
+		parts     = make(map[ToFormat][]string)
+		docRanges []PageRange
+	)
+	for i, resp := range resps {
+		if resp.Status != "success" {
+			merged.Status = "partial_success"
+		}
+		merged.Errors = append(merged.Errors, resp.Errors...)
+		merged.ProcessingTime += resp.ProcessingTime
+		merged.QueueWait = max(merged.QueueWait, resp.QueueWait)
+		for _, content := range resp.Document.Contents {
+			format := content.Format()
+			switch format {
+			case ToMarkdown, ToJSON, ToHTML, ToText, ToDocTags:
+			default:
+				return ConvertResponse{}, fmt.Errorf("cannot merge %s contents of shards", format)
+			}
+			s, err := readContent(content)
+			if err != nil {
+				return ConvertResponse{}, fmt.Errorf("failed to read %s content of pages %s: %w", format, ranges[i], err)
+			}
+			if s == "" || format == ToJSON && s == "null" {
+				continue
+			}
+			if format == ToJSON {
+				docRanges = append(docRanges, ranges[i])
+			}
+			if _, ok := parts[format]; !ok {
+				formats = append(formats, format)
+			}
+			parts[format] = append(parts[format], s)
+		}
+	}
+	for _, format := range formats {
+		var content Content
+		switch format {
+		case ToMarkdown:
+			content = MarkdownContent(strings.Join(parts[format], "\n\n"))
+		case ToText:
+			content = TextContent(strings.Join(parts[format], "\n\n"))
+		case ToDocTags:
+			content = DocTagsContent(strings.Join(parts[format], "\n"))
+		case ToHTML:
+			content = HTMLContent(mergeHTML(parts[format]))
+		case ToJSON:
+			docs := make([]json.RawMessage, len(parts[format]))
+			for i, s := range parts[format] {
+				docs[i] = json.RawMessage(s)
+			}
+			doc, err := mergeDoclingDocuments(docs, docRanges)
+			if err != nil {
+				return ConvertResponse{}, err
+			}
+			content = JSONContent(doc)
+		}
+		merged.Document.Contents = append(merged.Document.Contents, content)
+	}
+	return merged, nil
+}
+
+// mergeHTML moves the bodies of the pages into the body of the first one.
+func mergeHTML(pages []string) string {
+	bodies := make([]string, len(pages))
+	for i, page := range pages {
+		start, end, ok := htmlBody(page)
+		if !ok {
+			return strings.Join(pages, "\n")
+		}
+		bodies[i] = page[start:end]
+	}
+	start, end, _ := htmlBody(pages[0])
+	return pages[0][:start] + strings.Join(bodies, "\n") + pages[0][end:]
+}
+
+// htmlBody returns the bounds of the content of the body element of page.
+func htmlBody(page string) (int, int, bool) {
+	lower := strings.ToLower(page)
+	open := strings.Index(lower, "<body")
+	if open < 0 {
+		return 0, 0, false
+	}
+	start := strings.IndexByte(lower[open:], '>')
+	end := strings.LastIndex(lower, "</body>")
+	if start < 0 || end < open+start+1 {
+		return 0, 0, false
+	}
+	return open + start + 1, end, true
+}
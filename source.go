@@ -0,0 +1,226 @@
+package docling
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+)
+
+// sourceContent is the raw content of a SourceFile built by one of the
+// SourceFrom constructors, it is base64 encoded while the request is sent.
+type sourceContent struct {
+	open func() (io.ReadCloser, error)
+	// oneShot is set when the content can only be read once
+	oneShot bool
+	peek    func(n int) ([]byte, error)
+}
+
+func SourceFromBytes(filename string, data []byte) SourceFile {
+	return SourceFile{
+		Filename: filename,
+		content: &sourceContent{
+			open: func() (io.ReadCloser, error) {
+				return io.NopCloser(bytes.NewReader(data)), nil
+			},
+			peek: func(n int) ([]byte, error) {
+				return data[:min(n, len(data))], nil
+			},
+		},
+	}
+}
+
+// SourceFromPath returns a source reading the file each time the request is
+// sent, the file is not loaded in memory.
+func SourceFromPath(path string) (SourceFile, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return SourceFile{}, err
+	}
+	if !fi.Mode().IsRegular() {
+		return SourceFile{}, fmt.Errorf("%s is not a regular file", path)
+	}
+	open := func() (io.ReadCloser, error) {
+		return os.Open(path)
+	}
+	return SourceFile{
+		Filename: filepath.Base(path),
+		content: &sourceContent{
+			open: open,
+			peek: func(n int) ([]byte, error) {
+				f, err := open()
+				if err != nil {
+					return nil, err
+				}
+				defer f.Close()
+				head := make([]byte, n)
+				n, err = io.ReadFull(f, head)
+				if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+					return nil, err
+				}
+				return head[:n], nil
+			},
+		},
+	}, nil
+}
+
+// SourceFromReader returns a source streaming r. The reader can only be
+// consumed once, so such a source cannot be sent twice nor be cached.
+func SourceFromReader(filename string, r io.Reader) SourceFile {
+	br := bufio.NewReaderSize(r, sniffLen)
+	var read atomic.Bool
+	return SourceFile{
+		Filename: filename,
+		content: &sourceContent{
+			open: func() (io.ReadCloser, error) {
+				if read.Swap(true) {
+					return nil, fmt.Errorf("source %q has already been read", filename)
+				}
+				return io.NopCloser(br), nil
+			},
+			oneShot: true,
+			peek: func(n int) ([]byte, error) {
+				head, err := br.Peek(n)
+				if err != nil && !errors.Is(err, io.EOF) {
+					return nil, err
+				}
+				return head, nil
+			},
+		},
+	}
+}
+
+func (s SourceFile) writeJSON(w io.Writer) error {
+	filename, err := json.Marshal(s.Filename)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, `{"kind":%q,"filename":%s,"base64_string":`, SourceKindFile, filename)
+	if err != nil {
+		return err
+	}
+	if s.content == nil {
+		// a caller-provided string may be wrapped or hold anything else to
+		// escape
+		encoded, err := json.Marshal(s.Base64String)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(encoded)
+		if err != nil {
+			return err
+		}
+	} else {
+		// the standard base64 alphabet has nothing to escape in JSON
+		_, err = io.WriteString(w, `"`)
+		if err != nil {
+			return err
+		}
+		r, err := s.content.open()
+		if err != nil {
+			return fmt.Errorf("failed to open source %q: %w", s.Filename, err)
+		}
+		defer r.Close()
+		enc := base64.NewEncoder(base64.StdEncoding, w)
+		_, err = io.Copy(enc, r)
+		if err != nil {
+			return fmt.Errorf("failed to read source %q: %w", s.Filename, err)
+		}
+		err = enc.Close()
+		if err != nil {
+			return err
+		}
+		_, err = io.WriteString(w, `"`)
+		if err != nil {
+			return err
+		}
+	}
+	_, err = io.WriteString(w, `}`)
+	return err
+}
+
+// head returns the first n bytes of the decoded source content.
+func (s SourceFile) head(n int) ([]byte, error) {
+	if s.content != nil {
+		return s.content.peek(n)
+	}
+	// the decoder skips the newlines of wrapped base64
+	dec := base64.NewDecoder(base64.StdEncoding, strings.NewReader(s.Base64String))
+	head, err := io.ReadAll(io.LimitReader(dec, int64(n)))
+	if err != nil && len(head) == 0 {
+		return nil, err
+	}
+	return head, nil
+}
+
+// jsonWriter is implemented by request bodies that are written to the
+// connection as they are encoded rather than buffered by json.Marshal.
+type jsonWriter interface {
+	writeJSON(w io.Writer) error
+}
+
+func (req ProcessURLRequest) writeJSON(w io.Writer) error {
+	options, err := json.Marshal(req.Options)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, `{"options":%s,"sources":[`, options)
+	if err != nil {
+		return err
+	}
+	for i, src := range req.Sources {
+		if i > 0 {
+			_, err = io.WriteString(w, ",")
+			if err != nil {
+				return err
+			}
+		}
+		if jw, ok := src.(jsonWriter); ok {
+			err = jw.writeJSON(w)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		data, err := json.Marshal(src)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		if err != nil {
+			return err
+		}
+	}
+	target, err := json.Marshal(req.Target)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, `],"target":%s}`, target)
+	return err
+}
+
+func (req ProcessURLRequest) replayable() bool {
+	for _, src := range req.Sources {
+		if sf, ok := asSourceFile(src); ok && sf.content != nil && sf.content.oneShot {
+			return false
+		}
+	}
+	return true
+}
+
+func asSourceFile(src Source) (SourceFile, bool) {
+	switch src := src.(type) {
+	case SourceFile:
+		return src, true
+	case *SourceFile:
+		return *src, true
+	}
+	return SourceFile{}, false
+}
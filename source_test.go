@@ -0,0 +1,100 @@
+package docling
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestProcessURLSources(t *testing.T) {
+	data := []byte("%PDF-1.7\nsome content")
+	path := filepath.Join(t.TempDir(), "doc.pdf")
+	err := os.WriteFile(path, data, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromPath, err := SourceFromPath(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []SourceFile
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Sources []SourceFile `json:"sources"`
+		}
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			t.Error(err)
+		}
+		got = req.Sources
+		w.Write([]byte(`{"status":"success"}`))
+	}))
+	defer srv.Close()
+	c, err := NewClient(ClientConfig{BaseURL: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = c.ProcessURL(context.Background(), ProcessURLRequest{
+		Sources: []Source{
+			SourceFromBytes("doc.pdf", data),
+			fromPath,
+			SourceFromReader("doc.pdf", bytes.NewReader(data)),
+			SourceFile{Filename: "doc.pdf", Base64String: base64.StdEncoding.EncodeToString(data)},
+		},
+		Target: TargetInBody{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 4 {
+		t.Fatalf("expected 4 sources, got %d", len(got))
+	}
+	expected := base64.StdEncoding.EncodeToString(data)
+	for i, src := range got {
+		if src.Filename != "doc.pdf" || src.Base64String != expected {
+			t.Fatalf("source %d: unexpected content %+v", i, src)
+		}
+	}
+	// a reader source can only be sent once
+	src := SourceFromReader("doc.pdf", strings.NewReader("content"))
+	_, err = src.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = src.MarshalJSON()
+	if err == nil {
+		t.Fatal("expected an error when reading a reader source twice")
+	}
+}
+
+func TestSourceFileBase64String(t *testing.T) {
+	encoded := base64.StdEncoding.EncodeToString([]byte("%PDF-1.7\n" + strings.Repeat("x", 100)))
+	wrapped := encoded[:40] + "\r\n" + encoded[40:] + "\n"
+	src := SourceFile{Filename: `say "hi".pdf`, Base64String: wrapped}
+	data, err := src.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded SourceFile
+	err = json.Unmarshal(data, &decoded)
+	if err != nil {
+		t.Fatalf("invalid source JSON %s: %v", data, err)
+	}
+	if decoded.Base64String != wrapped || decoded.Filename != src.Filename {
+		t.Fatalf("unexpected source %+v", decoded)
+	}
+	format, err := DetectSourceFormat(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if format != FromPDF {
+		t.Fatalf("expected %s, got %s", FromPDF, format)
+	}
+}
@@ -0,0 +1,612 @@
+package docling
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+var ErrResponseTooLarge = errors.New("response exceeds the maximum size")
+
+func WithMaxResponseSize(n int64) ClientOption {
+	return func(c *Client) {
+		c.maxResponseSize = n
+	}
+}
+
+// WithResponseSpooling decodes conversion responses as they are read. Document
+// contents larger than threshold bytes are written to temporary files in dir
+// (os.TempDir when empty) instead of being held in memory, they are returned
+// as *SpooledContent and removed by Document.Close.
+func WithResponseSpooling(threshold int64, dir string) ClientOption {
+	return func(c *Client) {
+		c.spool = &spoolConfig{threshold: threshold, dir: dir}
+	}
+}
+
+type spoolConfig struct {
+	threshold int64
+	dir       string
+}
+
+type limitedReader struct {
+	r     io.Reader
+	n     int64
+	limit int64
+}
+
+func (lr *limitedReader) Read(p []byte) (int, error) {
+	if lr.n > lr.limit {
+		return 0, lr.err()
+	}
+	// read one byte past the limit to tell a body of exactly limit bytes
+	// from a larger one
+	if rest := lr.limit - lr.n + 1; int64(len(p)) > rest {
+		p = p[:rest]
+	}
+	n, err := lr.r.Read(p)
+	lr.n += int64(n)
+	if lr.n > lr.limit {
+		return n, lr.err()
+	}
+	return n, err
+}
+
+func (lr *limitedReader) err() error {
+	return fmt.Errorf("%w: limit is %d bytes", ErrResponseTooLarge, lr.limit)
+}
+
+// SpooledContent is a document content too large to be kept in memory, it is
+// stored in a temporary file until the Document is closed.
+type SpooledContent struct {
+	format ToFormat
+	path   string
+	size   int64
+}
+
+func (c *SpooledContent) Format() ToFormat {
+	return c.format
+}
+
+// String reads the whole content in memory, it is empty when the temporary
+// file cannot be read. Prefer Open for large contents, or Bytes to get the
+// read error.
+func (c *SpooledContent) String() string {
+	data, _ := c.Bytes()
+	return string(data)
+}
+
+// Bytes reads the whole content in memory.
+func (c *SpooledContent) Bytes() ([]byte, error) {
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read spooled %s content: %w", c.format, err)
+	}
+	return data, nil
+}
+
+// readContent returns the content as a string, with the read error of spooled
+// contents.
+func readContent(content Content) (string, error) {
+	if sc, ok := content.(*SpooledContent); ok {
+		data, err := sc.Bytes()
+		return string(data), err
+	}
+	return content.String(), nil
+}
+
+func (c *SpooledContent) Open() (io.ReadCloser, error) {
+	return os.Open(c.path)
+}
+
+func (c *SpooledContent) Size() int64 {
+	return c.size
+}
+
+func (c *SpooledContent) Close() error {
+	err := os.Remove(c.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// spool keeps written data in memory up to its threshold, then moves it to a
+// temporary file.
+type spool struct {
+	cfg  *spoolConfig
+	buf  bytes.Buffer
+	f    *os.File
+	size int64
+}
+
+func (s *spool) Write(p []byte) (int, error) {
+	s.size += int64(len(p))
+	if s.f == nil && int64(s.buf.Len()+len(p)) <= s.cfg.threshold {
+		return s.buf.Write(p)
+	}
+	if s.f == nil {
+		f, err := os.CreateTemp(s.cfg.dir, "docling-content-*")
+		if err != nil {
+			return 0, err
+		}
+		s.f = f
+		_, err = s.buf.WriteTo(f)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return s.f.Write(p)
+}
+
+// content returns the spooled data as a Content of the given format.
+func (s *spool) content(format ToFormat) (Content, error) {
+	if s.f == nil {
+		return newContent(format, s.buf.Bytes()), nil
+	}
+	err := s.f.Close()
+	if err != nil {
+		os.Remove(s.f.Name())
+		return nil, err
+	}
+	return &SpooledContent{format: format, path: s.f.Name(), size: s.size}, nil
+}
+
+// file returns a File reading the spooled data, with a known size.
+func (s *spool) file(name string) File {
+	if s.f == nil {
+		return FileReader{Filename: name, Reader: bytes.NewReader(s.buf.Bytes())}
+	}
+	return FileReader{Filename: name, Reader: io.NewSectionReader(s.f, 0, s.size)}
+}
+
+func (s *spool) discard() {
+	if s.f != nil {
+		s.f.Close()
+		os.Remove(s.f.Name())
+	}
+}
+
+// contentFields maps the document fields of a response to their format, the
+// other *_content fields are named after their format.
+var contentFields = map[string]ToFormat{
+	"md_content":      ToMarkdown,
+	"json_content":    ToJSON,
+	"html_content":    ToHTML,
+	"text_content":    ToText,
+	"doctags_content": ToDocTags,
+}
+
+// contentFormat returns the format of a document field, ok is false when the
+// field is not a content.
+func contentFormat(key string) (ToFormat, bool) {
+	if format, ok := contentFields[key]; ok {
+		return format, true
+	}
+	name, ok := strings.CutSuffix(key, "_content")
+	if !ok || !isFormatName(name) {
+		return "", false
+	}
+	return ToFormat(name), true
+}
+
+// isFormatName reports whether name is made of lowercase letters, digits and
+// underscores only, so it can be used in file names.
+func isFormatName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, c := range name {
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '_' {
+			return false
+		}
+	}
+	return true
+}
+
+func newContent(format ToFormat, data []byte) Content {
+	switch format {
+	case ToMarkdown:
+		return MarkdownContent(data)
+	case ToJSON:
+		return JSONContent(bytes.Clone(data))
+	case ToHTML:
+		return HTMLContent(data)
+	case ToHTMLSplitPage:
+		return HTMLSplitPageContent(data)
+	case ToText:
+		return TextContent(data)
+	case ToDocTags:
+		return DocTagsContent(data)
+	}
+	return RawContent{ContentFormat: format, Data: string(data)}
+}
+
+// Close removes the temporary files of the spooled contents of the document.
+func (d Document) Close() error {
+	var errs []error
+	for _, content := range d.Contents {
+		if c, ok := content.(io.Closer); ok {
+			errs = append(errs, c.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// decodeStream decodes a conversion response, document contents are written
+// to spools as they are read so they are never held twice in memory.
+func (resp *ConvertResponse) decodeStream(r io.Reader, cfg *spoolConfig) (err error) {
+	s := jsonScanner{r: bufio.NewReader(r)}
+	var rest bytes.Buffer
+	rest.WriteByte('{')
+	var doc Document
+	defer func() {
+		if err != nil {
+			doc.Close()
+		}
+	}()
+	err = s.object(func(key string) error {
+		if key == "document" {
+			return s.document(&doc, cfg)
+		}
+		if rest.Len() > 1 {
+			rest.WriteByte(',')
+		}
+		k, err := json.Marshal(key)
+		if err != nil {
+			return err
+		}
+		rest.Write(k)
+		rest.WriteByte(':')
+		return s.copyValue(&rest)
+	})
+	if err != nil {
+		return err
+	}
+	rest.WriteByte('}')
+	err = json.Unmarshal(rest.Bytes(), resp)
+	if err != nil {
+		return err
+	}
+	sortContents(doc.Contents)
+	resp.Document = doc
+	return nil
+}
+
+func (s *jsonScanner) document(doc *Document, cfg *spoolConfig) error {
+	c, err := s.peek()
+	if err != nil {
+		return err
+	}
+	if c == 'n' {
+		return s.copyValue(io.Discard)
+	}
+	return s.object(func(key string) error {
+		format, ok := contentFormat(key)
+		if !ok {
+			var raw bytes.Buffer
+			err := s.copyValue(&raw)
+			if err != nil {
+				return err
+			}
+			if key == "filename" {
+				return json.Unmarshal(raw.Bytes(), &doc.Filename)
+			}
+			return nil
+		}
+		c, err := s.peek()
+		if err != nil {
+			return err
+		}
+		sp := &spool{cfg: cfg}
+		bw := bufio.NewWriter(sp)
+		// values of unknown formats which are not strings are kept as JSON
+		rawJSON := false
+		switch {
+		case c == 'n':
+			return s.copyValue(io.Discard)
+		case c == '"' && format != ToJSON:
+			s.r.ReadByte()
+			err = s.unquote(bw)
+		default:
+			rawJSON = format != ToJSON
+			err = s.copyValue(bw)
+		}
+		if err == nil {
+			err = bw.Flush()
+		}
+		if err != nil {
+			sp.discard()
+			return err
+		}
+		if sp.size == 0 {
+			return nil
+		}
+		content, err := sp.content(format)
+		if err != nil {
+			return err
+		}
+		if rc, ok := content.(RawContent); ok && rawJSON {
+			rc.JSON = true
+			content = rc
+		}
+		doc.Contents = append(doc.Contents, content)
+		return nil
+	})
+}
+
+// jsonScanner reads JSON values from a stream without buffering them.
+type jsonScanner struct {
+	r *bufio.Reader
+}
+
+func (s *jsonScanner) next() (byte, error) {
+	for {
+		c, err := s.r.ReadByte()
+		if err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return 0, err
+		}
+		switch c {
+		case ' ', '\t', '\r', '\n':
+			continue
+		}
+		return c, nil
+	}
+}
+
+func (s *jsonScanner) peek() (byte, error) {
+	c, err := s.next()
+	if err != nil {
+		return 0, err
+	}
+	return c, s.r.UnreadByte()
+}
+
+func (s *jsonScanner) expect(want byte) error {
+	c, err := s.next()
+	if err != nil {
+		return err
+	}
+	if c != want {
+		return fmt.Errorf("invalid character %q, expected %q", c, want)
+	}
+	return nil
+}
+
+// object calls fn for each key of an object, fn must consume the value.
+func (s *jsonScanner) object(fn func(key string) error) error {
+	err := s.expect('{')
+	if err != nil {
+		return err
+	}
+	c, err := s.peek()
+	if err != nil {
+		return err
+	}
+	if c == '}' {
+		s.r.ReadByte()
+		return nil
+	}
+	for {
+		err = s.expect('"')
+		if err != nil {
+			return err
+		}
+		var key bytes.Buffer
+		err = s.unquote(&key)
+		if err != nil {
+			return err
+		}
+		err = s.expect(':')
+		if err != nil {
+			return err
+		}
+		err = fn(key.String())
+		if err != nil {
+			return err
+		}
+		c, err := s.next()
+		if err != nil {
+			return err
+		}
+		switch c {
+		case ',':
+		case '}':
+			return nil
+		default:
+			return fmt.Errorf("invalid character %q after object value", c)
+		}
+	}
+}
+
+// unquote writes the unescaped content of a string whose opening quote has
+// already been read.
+func (s *jsonScanner) unquote(w io.Writer) error {
+	var buf [utf8.UTFMax]byte
+	for {
+		c, err := s.r.ReadByte()
+		if err != nil {
+			return unexpectedEOF(err)
+		}
+		switch c {
+		case '"':
+			return nil
+		case '\\':
+			c, err = s.r.ReadByte()
+			if err != nil {
+				return unexpectedEOF(err)
+			}
+			switch c {
+			case '"', '\\', '/':
+			case 'b':
+				c = '\b'
+			case 'f':
+				c = '\f'
+			case 'n':
+				c = '\n'
+			case 'r':
+				c = '\r'
+			case 't':
+				c = '\t'
+			case 'u':
+				r, err := s.unicode()
+				if err != nil {
+					return err
+				}
+				n := utf8.EncodeRune(buf[:], r)
+				_, err = w.Write(buf[:n])
+				if err != nil {
+					return err
+				}
+				continue
+			default:
+				return fmt.Errorf("invalid escape character %q", c)
+			}
+		}
+		buf[0] = c
+		_, err = w.Write(buf[:1])
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (s *jsonScanner) unicode() (rune, error) {
+	r, err := s.hex4()
+	if err != nil {
+		return 0, err
+	}
+	if !utf16.IsSurrogate(r) {
+		return r, nil
+	}
+	// a surrogate must be followed by its pair
+	next, err := s.r.Peek(2)
+	if err != nil || string(next) != `\u` {
+		return utf8.RuneError, nil
+	}
+	s.r.Discard(2)
+	r2, err := s.hex4()
+	if err != nil {
+		return 0, err
+	}
+	return utf16.DecodeRune(r, r2), nil
+}
+
+func (s *jsonScanner) hex4() (rune, error) {
+	var r rune
+	for range 4 {
+		c, err := s.r.ReadByte()
+		if err != nil {
+			return 0, unexpectedEOF(err)
+		}
+		switch {
+		case '0' <= c && c <= '9':
+			c -= '0'
+		case 'a' <= c && c <= 'f':
+			c = c - 'a' + 10
+		case 'A' <= c && c <= 'F':
+			c = c - 'A' + 10
+		default:
+			return 0, fmt.Errorf("invalid character %q in unicode escape", c)
+		}
+		r = r<<4 | rune(c)
+	}
+	return r, nil
+}
+
+// copyValue copies the raw encoding of the next value to w.
+func (s *jsonScanner) copyValue(w io.Writer) error {
+	bw, ok := w.(io.ByteWriter)
+	if !ok {
+		b := bufio.NewWriter(w)
+		defer b.Flush()
+		bw = b
+	}
+	c, err := s.next()
+	if err != nil {
+		return err
+	}
+	depth := 0
+	for {
+		err = bw.WriteByte(c)
+		if err != nil {
+			return err
+		}
+		switch c {
+		case '{', '[':
+			depth++
+		case '}', ']':
+			depth--
+		case ',', ':':
+		case '"':
+			err = s.copyString(bw)
+			if err != nil {
+				return err
+			}
+		default:
+			// literal: copy until the next delimiter
+			for {
+				next, err := s.r.Peek(1)
+				if err != nil && err != io.EOF {
+					return err
+				}
+				if len(next) == 0 || bytes.IndexByte([]byte(",}] \t\r\n"), next[0]) >= 0 {
+					break
+				}
+				s.r.ReadByte()
+				err = bw.WriteByte(next[0])
+				if err != nil {
+					return err
+				}
+			}
+		}
+		if depth == 0 {
+			return nil
+		}
+		c, err = s.next()
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// copyString copies a raw string, escapes included, whose opening quote has
+// already been copied.
+func (s *jsonScanner) copyString(bw io.ByteWriter) error {
+	escaped := false
+	for {
+		c, err := s.r.ReadByte()
+		if err != nil {
+			return unexpectedEOF(err)
+		}
+		err = bw.WriteByte(c)
+		if err != nil {
+			return err
+		}
+		switch {
+		case escaped:
+			escaped = false
+		case c == '\\':
+			escaped = true
+		case c == '"':
+			return nil
+		}
+	}
+}
+
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
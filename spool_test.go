@@ -0,0 +1,156 @@
+package docling
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestDecodeStream(t *testing.T) {
+	body := `{"document": {"filename": "doc.pdf", "md_content": "# Café 😀\n\n\"quoted\" \\ text, with } and ]", "json_content": {"name": "doc", "texts": [{"text": "a \"b\" }"}, 1, true, null]}, "html_content": null, "doctags_content": ""}, "status": "success", "errors": [], "processing_time": 1.5, "timings": {}}`
+	var expected ConvertResponse
+	err := json.Unmarshal([]byte(body), &expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, threshold := range []int64{1 << 20, 16} {
+		var got ConvertResponse
+		err := got.decodeStream(strings.NewReader(body), &spoolConfig{threshold: threshold, dir: t.TempDir()})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Status != expected.Status || got.ProcessingTime != expected.ProcessingTime || got.Document.Filename != expected.Document.Filename {
+			t.Fatalf("threshold %d: unexpected response %+v", threshold, got)
+		}
+		if len(got.Document.Contents) != 2 {
+			t.Fatalf("threshold %d: expected 2 contents, got %d", threshold, len(got.Document.Contents))
+		}
+		if got, expected := got.Document.MarkdownContent(), expected.Document.MarkdownContent(); got != expected {
+			t.Fatalf("threshold %d: expected markdown %q, got %q", threshold, expected, got)
+		}
+		var gotJSON, expectedJSON any
+		json.Unmarshal([]byte(got.Document.JSONContent()), &gotJSON)
+		json.Unmarshal([]byte(expected.Document.JSONContent()), &expectedJSON)
+		if a, b := mustMarshal(t, gotJSON), mustMarshal(t, expectedJSON); a != b {
+			t.Fatalf("threshold %d: expected json %s, got %s", threshold, b, a)
+		}
+		spooled, ok := got.Document.Contents[0].(*SpooledContent)
+		if ok != (threshold == 16) {
+			t.Fatalf("threshold %d: unexpected spooled content %t", threshold, ok)
+		}
+		err = got.Document.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if spooled != nil {
+			if _, err := os.Stat(spooled.path); !errors.Is(err, os.ErrNotExist) {
+				t.Fatalf("spooled content was not removed: %v", err)
+			}
+		}
+	}
+}
+
+func TestDecodeContentFormats(t *testing.T) {
+	body := `{"document": {"filename": "doc.pdf", "text_content": "plain", "html_split_page_content": "<section>1</section>", "vtt_content": "WEBVTT", "graph_content": {"nodes":[1]}, "md_content": null, "doctags_content": "<doctag></doctag>"}, "status": "success"}`
+	var plain ConvertResponse
+	err := json.Unmarshal([]byte(body), &plain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var streamed ConvertResponse
+	err = streamed.decodeStream(strings.NewReader(body), &spoolConfig{threshold: 1 << 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, resp := range []ConvertResponse{plain, streamed} {
+		doc := resp.Document
+		var formats []ToFormat
+		for _, content := range doc.Contents {
+			formats = append(formats, content.Format())
+		}
+		if want := []ToFormat{ToDocTags, ToText, "graph", ToHTMLSplitPage, "vtt"}; !slices.Equal(formats, want) {
+			t.Fatalf("expected contents in order %v, got %v", want, formats)
+		}
+		if doc.TextContent() != "plain" || doc.contentString(ToHTMLSplitPage) != "<section>1</section>" {
+			t.Fatalf("unexpected contents %+v", doc.Contents)
+		}
+		for _, content := range doc.Contents {
+			switch content.Format() {
+			case "vtt":
+				if content != (RawContent{ContentFormat: "vtt", Data: "WEBVTT"}) {
+					t.Fatalf("unexpected vtt content %#v", content)
+				}
+			case "graph":
+				if content != (RawContent{ContentFormat: "graph", Data: `{"nodes":[1]}`, JSON: true}) {
+					t.Fatalf("unexpected graph content %#v", content)
+				}
+			}
+		}
+	}
+}
+
+func TestMaxResponseSize(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status":"ok"}`))
+	}))
+	defer srv.Close()
+	for limit, tooLarge := range map[int64]bool{15: false, 14: true} {
+		c, err := NewClient(ClientConfig{BaseURL: srv.URL}, WithMaxResponseSize(limit))
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = c.Health(context.Background())
+		if errors.Is(err, ErrResponseTooLarge) != tooLarge {
+			t.Fatalf("limit %d: unexpected error %v", limit, err)
+		}
+	}
+}
+
+func TestMaxResponseSizeHTTPError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, strings.Repeat("overloaded ", 100), http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+	c, err := NewClient(ClientConfig{BaseURL: srv.URL}, WithMaxResponseSize(20))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = c.Health(context.Background())
+	var httpErr HTTPError
+	if !errors.As(err, &httpErr) {
+		t.Fatalf("expected an HTTPError, got %v", err)
+	}
+	if httpErr.StatusCode != http.StatusServiceUnavailable || !httpErr.Truncated || len(httpErr.Body) != 20 {
+		t.Fatalf("unexpected error %+v", httpErr)
+	}
+}
+
+func TestSpooledContentReadError(t *testing.T) {
+	sc := &SpooledContent{format: ToMarkdown, path: filepath.Join(t.TempDir(), "missing")}
+	if _, err := sc.Bytes(); err == nil {
+		t.Fatal("expected an error reading a missing spool file")
+	}
+	doc := Document{Filename: "doc.pdf", Contents: []Content{sc}}
+	if _, err := json.Marshal(doc); err == nil {
+		t.Fatal("expected MarshalJSON to fail on a missing spool file")
+	}
+	if err := WriteArchive(io.Discard, ConvertResponse{Status: "success", Document: doc}); err == nil {
+		t.Fatal("expected WriteArchive to fail on a missing spool file")
+	}
+}
+
+func mustMarshal(t *testing.T, v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
@@ -0,0 +1,165 @@
+package docling
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Table is a table of a converted document, with the text of its captions
+// and footnotes resolved.
+type Table struct {
+	Ref       string
+	NumRows   int
+	NumCols   int
+	Cells     []TableCell
+	Caption   string
+	Footnotes []string
+	Prov      []ProvenanceItem
+}
+
+// Tables returns the tables of the json content of the document.
+func (d Document) Tables() ([]Table, error) {
+	doc, err := d.DoclingDocument()
+	if err != nil {
+		return nil, err
+	}
+	return doc.AllTables(), nil
+}
+
+// AllTables returns the tables of the document in document order.
+func (doc *DoclingDocument) AllTables() []Table {
+	tables := make([]Table, 0, len(doc.Tables))
+	for _, item := range doc.Tables {
+		tables = append(tables, doc.table(item))
+	}
+	return tables
+}
+
+func (doc *DoclingDocument) table(item TableItem) Table {
+	return Table{
+		Ref:       item.SelfRef,
+		NumRows:   item.Data.NumRows,
+		NumCols:   item.Data.NumCols,
+		Cells:     item.Data.TableCells,
+		Caption:   strings.Join(doc.texts(item.Captions), " "),
+		Footnotes: doc.texts(item.Footnotes),
+		Prov:      item.Prov,
+	}
+}
+
+// Grid returns the cells of the table by row and column, a cell spanning
+// several rows or columns appears at each of the positions it covers.
+func (t Table) Grid() [][]*TableCell {
+	grid := make([][]*TableCell, t.NumRows)
+	for i := range grid {
+		grid[i] = make([]*TableCell, t.NumCols)
+	}
+	for i := range t.Cells {
+		c := &t.Cells[i]
+		for row := max(c.StartRow, 0); row < min(max(c.EndRow, c.StartRow+1), t.NumRows); row++ {
+			for col := max(c.StartCol, 0); col < min(max(c.EndCol, c.StartCol+1), t.NumCols); col++ {
+				grid[row][col] = c
+			}
+		}
+	}
+	return grid
+}
+
+// Rows returns the text of the cells by row and column, the text of spanning
+// cells is repeated at each of the positions they cover.
+func (t Table) Rows() [][]string {
+	grid := t.Grid()
+	rows := make([][]string, len(grid))
+	for i, cells := range grid {
+		rows[i] = make([]string, len(cells))
+		for j, c := range cells {
+			if c != nil {
+				rows[i][j] = c.Text
+			}
+		}
+	}
+	return rows
+}
+
+// HeaderRows returns the number of leading rows made of column headers.
+func (t Table) HeaderRows() int {
+	n := 0
+	for _, cells := range t.Grid() {
+		for _, c := range cells {
+			if c == nil || !c.ColumnHeader {
+				return n
+			}
+		}
+		n++
+	}
+	return n
+}
+
+// Header returns the name of each column: the text of its header cells,
+// joined when the header spans several rows.
+func (t Table) Header() []string {
+	grid := t.Grid()
+	header := make([]string, t.NumCols)
+	for col := range header {
+		var parts []string
+		var prev *TableCell
+		for _, cells := range grid[:t.HeaderRows()] {
+			c := cells[col]
+			if c == prev || c.Text == "" {
+				continue
+			}
+			prev = c
+			parts = append(parts, c.Text)
+		}
+		header[col] = strings.Join(parts, " ")
+	}
+	return header
+}
+
+// Records returns the rows following the header as records keyed by column
+// name. Unnamed columns are named column_<n>, and repeated names get a
+// numbered suffix.
+func (t Table) Records() []map[string]string {
+	keys := t.Header()
+	seen := make(map[string]int, len(keys))
+	for i, key := range keys {
+		if key == "" {
+			key = fmt.Sprintf("column_%d", i+1)
+		}
+		seen[key]++
+		if n := seen[key]; n > 1 {
+			key = fmt.Sprintf("%s_%d", key, n)
+		}
+		keys[i] = key
+	}
+	rows := t.Rows()[t.HeaderRows():]
+	records := make([]map[string]string, 0, len(rows))
+	for _, row := range rows {
+		record := make(map[string]string, len(row))
+		for i, text := range row {
+			record[keys[i]] = text
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func (t Table) WriteCSV(w io.Writer) error {
+	return t.write(w, ',')
+}
+
+func (t Table) WriteTSV(w io.Writer) error {
+	return t.write(w, '\t')
+}
+
+func (t Table) write(w io.Writer, comma rune) error {
+	cw := csv.NewWriter(w)
+	cw.Comma = comma
+	err := cw.WriteAll(t.Rows())
+	if err != nil {
+		return fmt.Errorf("failed to write table %s: %w", t.Ref, err)
+	}
+	return nil
+}
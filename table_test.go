@@ -0,0 +1,88 @@
+package docling
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+const tableDocument = `{
+	"schema_name": "DoclingDocument",
+	"version": "1.3.0",
+	"name": "report",
+	"body": {"self_ref": "#/body", "children": [{"$ref": "#/tables/0"}]},
+	"texts": [{"self_ref": "#/texts/0", "label": "caption", "orig": "Table 1: Sales", "text": "Table 1: Sales", "prov": []}],
+	"tables": [{
+		"self_ref": "#/tables/0",
+		"label": "table",
+		"captions": [{"$ref": "#/texts/0"}],
+		"prov": [{"page_no": 2, "bbox": {"l": 10, "t": 20, "r": 200, "b": 100, "coord_origin": "BOTTOMLEFT"}, "charspan": [0, 0]}],
+		"data": {
+			"num_rows": 4,
+			"num_cols": 3,
+			"table_cells": [
+				{"text": "Region", "row_span": 2, "col_span": 1, "start_row_offset_idx": 0, "end_row_offset_idx": 2, "start_col_offset_idx": 0, "end_col_offset_idx": 1, "column_header": true},
+				{"text": "Sales", "row_span": 1, "col_span": 2, "start_row_offset_idx": 0, "end_row_offset_idx": 1, "start_col_offset_idx": 1, "end_col_offset_idx": 3, "column_header": true},
+				{"text": "2024", "row_span": 1, "col_span": 1, "start_row_offset_idx": 1, "end_row_offset_idx": 2, "start_col_offset_idx": 1, "end_col_offset_idx": 2, "column_header": true},
+				{"text": "2025", "row_span": 1, "col_span": 1, "start_row_offset_idx": 1, "end_row_offset_idx": 2, "start_col_offset_idx": 2, "end_col_offset_idx": 3, "column_header": true},
+				{"text": "North", "row_span": 1, "col_span": 1, "start_row_offset_idx": 2, "end_row_offset_idx": 3, "start_col_offset_idx": 0, "end_col_offset_idx": 1, "row_header": true},
+				{"text": "1", "row_span": 1, "col_span": 1, "start_row_offset_idx": 2, "end_row_offset_idx": 3, "start_col_offset_idx": 1, "end_col_offset_idx": 2},
+				{"text": "2", "row_span": 1, "col_span": 1, "start_row_offset_idx": 2, "end_row_offset_idx": 3, "start_col_offset_idx": 2, "end_col_offset_idx": 3},
+				{"text": "South, East", "row_span": 1, "col_span": 1, "start_row_offset_idx": 3, "end_row_offset_idx": 4, "start_col_offset_idx": 0, "end_col_offset_idx": 1, "row_header": true},
+				{"text": "3", "row_span": 1, "col_span": 2, "start_row_offset_idx": 3, "end_row_offset_idx": 4, "start_col_offset_idx": 1, "end_col_offset_idx": 3}
+			]
+		}
+	}],
+	"pages": {"2": {"size": {"width": 612, "height": 792}, "page_no": 2}}
+}`
+
+func TestTables(t *testing.T) {
+	doc := Document{Contents: []Content{JSONContent(tableDocument)}}
+	tables, err := doc.Tables()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tables) != 1 {
+		t.Fatalf("expected 1 table, got %d", len(tables))
+	}
+	table := tables[0]
+	if table.Caption != "Table 1: Sales" || table.Prov[0].PageNo != 2 {
+		t.Fatalf("unexpected table %+v", table)
+	}
+	if n := table.HeaderRows(); n != 2 {
+		t.Fatalf("expected 2 header rows, got %d", n)
+	}
+	wantHeader := []string{"Region", "Sales 2024", "Sales 2025"}
+	if header := table.Header(); !reflect.DeepEqual(header, wantHeader) {
+		t.Fatalf("expected header %q, got %q", wantHeader, header)
+	}
+	wantRecords := []map[string]string{
+		{"Region": "North", "Sales 2024": "1", "Sales 2025": "2"},
+		{"Region": "South, East", "Sales 2024": "3", "Sales 2025": "3"},
+	}
+	if records := table.Records(); !reflect.DeepEqual(records, wantRecords) {
+		t.Fatalf("expected records %v, got %v", wantRecords, records)
+	}
+	var b bytes.Buffer
+	err = table.WriteCSV(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantCSV := "Region,Sales,Sales\nRegion,2024,2025\nNorth,1,2\n\"South, East\",3,3\n"
+	if b.String() != wantCSV {
+		t.Fatalf("expected csv %q, got %q", wantCSV, b.String())
+	}
+	b.Reset()
+	err = table.WriteTSV(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantTSV := "Region\tSales\tSales\nRegion\t2024\t2025\nNorth\t1\t2\nSouth, East\t3\t3\n"
+	if b.String() != wantTSV {
+		t.Fatalf("expected tsv %q, got %q", wantTSV, b.String())
+	}
+	_, err = Document{}.Tables()
+	if err != ErrNoJSONContent {
+		t.Fatalf("expected ErrNoJSONContent, got %v", err)
+	}
+}
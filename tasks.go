@@ -4,13 +4,24 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strconv"
+	"time"
 )
 
 func (c *Client) PollTaskStatus(ctx context.Context, taskID string) (AsyncResponse, error) {
+	return c.PollTaskStatusWait(ctx, taskID, 0)
+}
+
+// PollTaskStatusWait long-polls the status of a task: the server holds the
+// request until the task completes or wait elapses.
+func (c *Client) PollTaskStatusWait(ctx context.Context, taskID string, wait time.Duration) (AsyncResponse, error) {
 	r, err := c.NewRequest(ctx, http.MethodGet, fmt.Sprintf("status/poll/%s", taskID), nil)
 	if err != nil {
 		return AsyncResponse{}, err
 	}
+	if wait > 0 {
+		r.URL.RawQuery = "wait=" + strconv.FormatFloat(wait.Seconds(), 'f', -1, 64)
+	}
 	var resp AsyncResponse
 	err = c.Do(r, &resp)
 	if err != nil {
@@ -31,3 +42,23 @@ func (c *Client) GetConvertTaskResult(ctx context.Context, taskID string) (Conve
 	}
 	return resp, nil
 }
+
+func (c *Client) waitTask(ctx context.Context, taskID string, interval time.Duration) (AsyncResponse, error) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		resp, err := c.PollTaskStatus(ctx, taskID)
+		if err != nil {
+			return AsyncResponse{}, err
+		}
+		switch resp.TaskStatus {
+		case "success", "failure":
+			return resp, nil
+		}
+		select {
+		case <-ctx.Done():
+			return AsyncResponse{}, ctx.Err()
+		case <-t.C:
+		}
+	}
+}
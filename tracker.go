@@ -0,0 +1,361 @@
+package docling
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Job is an async conversion task recorded by a JobTracker.
+type Job struct {
+	TaskID      string            `json:"task_id"`
+	Inputs      []string          `json:"inputs"`  // file names, URLs or S3 locations
+	Options     ConvertOptions    `json:"options"` // without the headers and params of API models, which may hold credentials
+	Metadata    map[string]string `json:"metadata,omitempty"`
+	SubmittedAt time.Time         `json:"submitted_at"`
+}
+
+// JobStore persists the jobs of a JobTracker.
+type JobStore interface {
+	Put(ctx context.Context, job Job) error
+	Delete(ctx context.Context, taskID string) error
+	List(ctx context.Context) ([]Job, error)
+}
+
+// FileJobStore keeps jobs in a JSON file, rewritten atomically on each change.
+type FileJobStore struct {
+	path string
+	mu   sync.Mutex
+	jobs map[string]Job
+}
+
+func NewFileJobStore(path string) (*FileJobStore, error) {
+	s := &FileJobStore{path: path, jobs: make(map[string]Job)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read job store: %w", err)
+	}
+	var jobs []Job
+	err = json.Unmarshal(data, &jobs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode job store: %w", err)
+	}
+	for _, job := range jobs {
+		s.jobs[job.TaskID] = job
+	}
+	return s, nil
+}
+
+func (s *FileJobStore) Put(ctx context.Context, job Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs[job.TaskID] = job
+	return s.save()
+}
+
+func (s *FileJobStore) Delete(ctx context.Context, taskID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.jobs[taskID]; !ok {
+		return nil
+	}
+	delete(s.jobs, taskID)
+	return s.save()
+}
+
+func (s *FileJobStore) List(ctx context.Context) ([]Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.list(), nil
+}
+
+func (s *FileJobStore) list() []Job {
+	jobs := make([]Job, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		jobs = append(jobs, job)
+	}
+	slices.SortFunc(jobs, func(a, b Job) int {
+		return cmp.Or(a.SubmittedAt.Compare(b.SubmittedAt), strings.Compare(a.TaskID, b.TaskID))
+	})
+	return jobs
+}
+
+func (s *FileJobStore) save() error {
+	data, err := json.Marshal(s.list())
+	if err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(s.path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	// rename is atomic so a crash never leaves a partial store
+	return os.Rename(f.Name(), s.path)
+}
+
+type JobTrackerOptions struct {
+	Store         JobStore                          // required
+	Notifier      *Notifier                         // required: must be running
+	OnResult      func(Job, ConvertResponse, error) // required: called once per job as it completes, on its own goroutine
+	ResultRetries int                               // default: 5, retries of a failed result download
+	RetryInterval time.Duration                     // default: 1s, doubled after each retry up to 1m
+	// ClearOlderThan enables the removal of the results older than the given
+	// age from the server once a job is collected. Results younger than the
+	// oldest pending job are never cleared.
+	//
+	// WARNING: clearing is global on the server, it removes the results of
+	// every client, including results other clients have not fetched yet.
+	// Only set it when this tracker is the only user of the server.
+	ClearOlderThan time.Duration // default: 0, results are not cleared
+}
+
+// JobTracker records the async tasks it submits in a JobStore, and collects
+// their results once they complete. Jobs left pending by a previous process
+// are resumed by Resume.
+type JobTracker struct {
+	c    *Client
+	opts JobTrackerOptions
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	mu     sync.Mutex
+	closed bool
+	wg     sync.WaitGroup
+}
+
+func (c *Client) NewJobTracker(opts JobTrackerOptions) (*JobTracker, error) {
+	if opts.Store == nil || opts.Notifier == nil || opts.OnResult == nil {
+		return nil, errors.New("job tracker requires a store, a notifier and a result callback")
+	}
+	if opts.ResultRetries <= 0 {
+		opts.ResultRetries = 5
+	}
+	if opts.RetryInterval <= 0 {
+		opts.RetryInterval = time.Second
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &JobTracker{c: c, opts: opts, ctx: ctx, cancel: cancel}, nil
+}
+
+// Close stops the result downloads in flight and waits for them to return.
+// The jobs which were not reported are kept in the store, to be collected on
+// the next Resume.
+func (t *JobTracker) Close() error {
+	t.mu.Lock()
+	t.closed = true
+	t.mu.Unlock()
+	t.cancel()
+	t.wg.Wait()
+	return nil
+}
+
+// ProcessFileAsync submits the request and records the job.
+func (t *JobTracker) ProcessFileAsync(ctx context.Context, req ProcessFileRequest, metadata map[string]string) (Job, error) {
+	task, err := t.c.ProcessFileAsync(ctx, req)
+	if err != nil {
+		return Job{}, err
+	}
+	job := Job{
+		TaskID:      task.TaskID,
+		Options:     redactOptions(req.ConvertOptions),
+		Metadata:    metadata,
+		SubmittedAt: time.Now(),
+	}
+	for _, f := range req.Files {
+		job.Inputs = append(job.Inputs, f.Name())
+	}
+	return job, t.track(ctx, job)
+}
+
+// ProcessURLAsync submits the request and records the job.
+func (t *JobTracker) ProcessURLAsync(ctx context.Context, req ProcessURLRequest, metadata map[string]string) (Job, error) {
+	task, err := t.c.ProcessURLAsync(ctx, req)
+	if err != nil {
+		return Job{}, err
+	}
+	job := Job{
+		TaskID:      task.TaskID,
+		Options:     redactOptions(req.Options),
+		Metadata:    metadata,
+		SubmittedAt: time.Now(),
+	}
+	for _, src := range req.Sources {
+		job.Inputs = append(job.Inputs, sourceName(src))
+	}
+	return job, t.track(ctx, job)
+}
+
+// Resume waits again for the jobs found in the store.
+func (t *JobTracker) Resume(ctx context.Context) error {
+	jobs, err := t.opts.Store.List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list jobs: %w", err)
+	}
+	for _, job := range jobs {
+		t.subscribe(job)
+	}
+	return nil
+}
+
+func (t *JobTracker) track(ctx context.Context, job Job) error {
+	err := t.opts.Store.Put(ctx, job)
+	if err != nil {
+		return fmt.Errorf("failed to record job %s: %w", job.TaskID, err)
+	}
+	t.subscribe(job)
+	return nil
+}
+
+func (t *JobTracker) subscribe(job Job) {
+	t.opts.Notifier.Subscribe(job.TaskID, func(ev TaskEvent) {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		if t.closed {
+			return
+		}
+		// notifier callbacks must not block
+		t.wg.Go(func() {
+			t.collect(t.ctx, job, ev)
+		})
+	})
+}
+
+func (t *JobTracker) collect(ctx context.Context, job Job, ev TaskEvent) {
+	var (
+		resp ConvertResponse
+		err  = ev.Err
+	)
+	if err == nil && ev.Status.TaskStatus != "success" {
+		err = fmt.Errorf("task %s ended with status %q", job.TaskID, ev.Status.TaskStatus)
+	}
+	if err == nil {
+		resp, err = t.result(ctx, job)
+	}
+	if ctx.Err() != nil {
+		// the tracker was closed, the job is collected on the next resume
+		resp.Document.Close()
+		return
+	}
+	// the job is reported once, it is deleted even when its result could not
+	// be downloaded
+	t.opts.OnResult(job, resp, err)
+	err = t.opts.Store.Delete(ctx, job.TaskID)
+	if err != nil {
+		t.c.logger.ErrorContext(ctx, "failed to delete job", slog.String("task_id", job.TaskID), slog.Any("error", err))
+	}
+	t.clear(ctx)
+}
+
+// result downloads the result of a job, retrying with an exponential backoff.
+func (t *JobTracker) result(ctx context.Context, job Job) (ConvertResponse, error) {
+	interval := t.opts.RetryInterval
+	for attempt := 0; ; attempt++ {
+		resp, err := t.c.GetConvertTaskResult(ctx, job.TaskID)
+		if err == nil {
+			return resp, nil
+		}
+		var httpErr HTTPError
+		if attempt >= t.opts.ResultRetries || errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusNotFound {
+			return ConvertResponse{}, fmt.Errorf("failed to get result of task %s: %w", job.TaskID, err)
+		}
+		t.c.logger.WarnContext(ctx, "retrying result download", slog.String("task_id", job.TaskID), slog.Int("attempt", attempt+1), slog.Any("error", err))
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ConvertResponse{}, ctx.Err()
+		case <-timer.C:
+		}
+		interval = min(2*interval, time.Minute)
+	}
+}
+
+func (t *JobTracker) clear(ctx context.Context) {
+	if t.opts.ClearOlderThan <= 0 {
+		return
+	}
+	jobs, err := t.opts.Store.List(ctx)
+	if err != nil {
+		t.c.logger.ErrorContext(ctx, "failed to list jobs", slog.Any("error", err))
+		return
+	}
+	olderThan := t.opts.ClearOlderThan
+	for _, job := range jobs {
+		// the result of a pending job is younger than the job itself
+		olderThan = max(olderThan, time.Since(job.SubmittedAt))
+	}
+	_, err = t.c.ClearResults(ctx, olderThan)
+	if err != nil {
+		t.c.logger.ErrorContext(ctx, "failed to clear results", slog.Any("error", err))
+	}
+}
+
+// redactOptions drops the headers and params of the API models from the
+// options, since they may hold credentials which must not be stored.
+func redactOptions(opts ConvertOptions) ConvertOptions {
+	if api := opts.PictureDescriptionAPI; api != nil {
+		redacted := *api
+		redacted.Headers, redacted.Params = nil, nil
+		opts.PictureDescriptionAPI = &redacted
+	}
+	if api := opts.VLMPipelineModelAPI; api != nil {
+		redacted := *api
+		redacted.Headers, redacted.Params = nil, nil
+		opts.VLMPipelineModelAPI = &redacted
+	}
+	return opts
+}
+
+// redactURL drops the user info and the query of a URL, which may hold
+// credentials such as the signature of a presigned URL.
+func redactURL(s string) string {
+	u, err := url.Parse(s)
+	if err != nil {
+		return "invalid URL"
+	}
+	u.User = nil
+	u.RawQuery = ""
+	u.ForceQuery = false
+	u.Fragment = ""
+	return u.String()
+}
+
+func sourceName(src Source) string {
+	switch src := src.(type) {
+	case SourceHTTP:
+		return redactURL(src.URL)
+	case *SourceHTTP:
+		return redactURL(src.URL)
+	case SourceS3:
+		return fmt.Sprintf("s3://%s/%s", src.Bucket, src.KeyPrefix)
+	case *SourceS3:
+		return fmt.Sprintf("s3://%s/%s", src.Bucket, src.KeyPrefix)
+	}
+	if sf, ok := asSourceFile(src); ok {
+		return sf.Filename
+	}
+	return string(src.Kind())
+}
@@ -0,0 +1,153 @@
+package docling
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"net/textproto"
+	"path/filepath"
+	"strings"
+)
+
+// ProgressFunc is called as file bytes are uploaded, total is -1 when the size
+// of the files is not known in advance. It is called from the goroutine writing
+// the upload body, not from the goroutine of the convert call, so it must not
+// block and must synchronize its access to shared state.
+type ProgressFunc func(sent, total int64)
+
+var ErrUploadTooLarge = errors.New("upload exceeds the maximum size")
+
+func WithMaxUploadSize(n int64) ClientOption {
+	return func(c *Client) {
+		c.maxUploadSize = n
+	}
+}
+
+// fileSize returns the size of f when it can be known without reading it, or -1.
+func fileSize(f File) int64 {
+	var r any = f
+	if fr, ok := f.(FileReader); ok {
+		r = fr.Reader
+	}
+	// Len comes first: the Size of bytes.Reader and strings.Reader is the size
+	// of the whole buffer, including what was already read
+	switch r := r.(type) {
+	case interface{ Len() int }:
+		return int64(r.Len())
+	case interface{ Size() int64 }:
+		return r.Size()
+	case interface{ Stat() (fs.FileInfo, error) }:
+		fi, err := r.Stat()
+		if err != nil || !fi.Mode().IsRegular() {
+			return -1
+		}
+		if s, ok := r.(io.Seeker); ok {
+			offset, err := s.Seek(0, io.SeekCurrent)
+			if err != nil {
+				return -1
+			}
+			return fi.Size() - offset
+		}
+		return fi.Size()
+	}
+	return -1
+}
+
+func uploadSize(files []File) int64 {
+	var total int64
+	for _, f := range files {
+		size := fileSize(f)
+		if size < 0 {
+			return -1
+		}
+		total += size
+	}
+	return total
+}
+
+func (c *Client) checkUploadSize(files []File) (int64, error) {
+	total := uploadSize(files)
+	if c.maxUploadSize > 0 && total > c.maxUploadSize {
+		return total, fmt.Errorf("%w: %d bytes, limit is %d bytes", ErrUploadTooLarge, total, c.maxUploadSize)
+	}
+	return total, nil
+}
+
+// uploadCounter tracks the bytes of all the files of an upload.
+type uploadCounter struct {
+	sent     int64
+	total    int64
+	limit    int64
+	progress ProgressFunc
+	err      error
+}
+
+func (uc *uploadCounter) reader(r io.Reader) io.Reader {
+	return &countingReader{r: r, uc: uc}
+}
+
+type countingReader struct {
+	r  io.Reader
+	uc *uploadCounter
+}
+
+func (cr *countingReader) Read(p []byte) (int, error) {
+	if cr.uc.err != nil {
+		return 0, cr.uc.err
+	}
+	n, err := cr.r.Read(p)
+	if n > 0 {
+		cr.uc.sent += int64(n)
+		if cr.uc.limit > 0 && cr.uc.sent > cr.uc.limit {
+			// keep failing, bufio would otherwise replace the error by the EOF of the next read
+			cr.uc.err = fmt.Errorf("%w: limit is %d bytes", ErrUploadTooLarge, cr.uc.limit)
+			return n, cr.uc.err
+		}
+		if cr.uc.progress != nil {
+			cr.uc.progress(cr.uc.sent, cr.uc.total)
+		}
+	}
+	return n, err
+}
+
+var extContentTypes = map[string]string{
+	".docx":     "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+	".pptx":     "application/vnd.openxmlformats-officedocument.presentationml.presentation",
+	".xlsx":     "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	".md":       "text/markdown",
+	".csv":      "text/csv",
+	".adoc":     "text/asciidoc",
+	".asciidoc": "text/asciidoc",
+}
+
+// sniffContentType detects the content type of a file from its first bytes,
+// falling back on the extension when the content alone is not conclusive
+// (office documents are zip files, markdown is plain text...).
+func sniffContentType(name string, br *bufio.Reader) string {
+	head, _ := br.Peek(512)
+	ct := http.DetectContentType(head)
+	switch {
+	case ct == "application/octet-stream", ct == "application/zip", strings.HasPrefix(ct, "text/plain"):
+		ext := strings.ToLower(filepath.Ext(name))
+		if byExt, ok := extContentTypes[ext]; ok {
+			return byExt
+		}
+		if byExt := mime.TypeByExtension(ext); byExt != "" {
+			return byExt
+		}
+	}
+	return ct
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+func formFileHeader(fieldname, filename, contentType string) textproto.MIMEHeader {
+	h := make(textproto.MIMEHeader)
+	h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`, quoteEscaper.Replace(fieldname), quoteEscaper.Replace(filename)))
+	h.Set("Content-Type", contentType)
+	return h
+}
@@ -0,0 +1,91 @@
+package docling
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestProcessFileUpload(t *testing.T) {
+	var contentType string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f, fh, err := r.FormFile("files")
+		if err != nil {
+			// the client aborts the uploads above the limit
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		defer f.Close()
+		contentType = fh.Header.Get("Content-Type")
+		w.Write([]byte(`{"status":"success"}`))
+	}))
+	defer srv.Close()
+	c, err := NewClient(ClientConfig{BaseURL: srv.URL}, WithMaxUploadSize(1024))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := []byte("%PDF-1.7\n")
+	var sent, total int64
+	_, err = c.ProcessFile(context.Background(), ProcessFileRequest{
+		Files: []File{FileReader{Filename: "doc.pdf", Reader: bytes.NewReader(data)}},
+		Progress: func(s, t int64) {
+			sent, total = s, t
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if contentType != "application/pdf" {
+		t.Fatalf("expected application/pdf content type, got %q", contentType)
+	}
+	if sent != int64(len(data)) || total != int64(len(data)) {
+		t.Fatalf("expected progress %d/%d, got %d/%d", len(data), len(data), sent, total)
+	}
+	_, err = c.ProcessFile(context.Background(), ProcessFileRequest{
+		Files: []File{FileReader{Filename: "doc.pdf", Reader: bytes.NewReader(make([]byte, 2048))}},
+	})
+	if !errors.Is(err, ErrUploadTooLarge) {
+		t.Fatalf("expected upload too large error, got %v", err)
+	}
+	// the size of a plain reader is only known once read
+	_, err = c.ProcessFile(context.Background(), ProcessFileRequest{
+		Files: []File{FileReader{Filename: "doc.pdf", Reader: io.LimitReader(bytes.NewReader(make([]byte, 2048)), 2048)}},
+	})
+	if !errors.Is(err, ErrUploadTooLarge) {
+		t.Fatalf("expected upload too large error while streaming, got %v", err)
+	}
+}
+
+func TestFileSize(t *testing.T) {
+	br := bytes.NewReader([]byte("0123456789"))
+	br.Seek(4, io.SeekStart)
+	path := filepath.Join(t.TempDir(), "doc.pdf")
+	err := os.WriteFile(path, []byte("0123456789"), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	f.Seek(3, io.SeekStart)
+	for _, tc := range []struct {
+		file File
+		size int64
+	}{
+		{FileReader{Filename: "doc.pdf", Reader: br}, 6},
+		{FileReader{Filename: "doc.pdf", Reader: f}, 7},
+		{FileReader{Filename: "doc.pdf", Reader: io.MultiReader(br)}, -1},
+	} {
+		if size := fileSize(tc.file); size != tc.size {
+			t.Errorf("expected size %d, got %d", tc.size, size)
+		}
+	}
+}
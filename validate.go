@@ -0,0 +1,108 @@
+package docling
+
+import (
+	"errors"
+	"fmt"
+)
+
+// FieldError reports an invalid value, Field is the JSON path of the option.
+type FieldError struct {
+	Field  string
+	Reason string
+}
+
+func (e FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Reason)
+}
+
+func WithValidation(enable bool) ClientOption {
+	return func(c *Client) {
+		c.skipValidation = !enable
+	}
+}
+
+// Validate checks the options for combinations docling-serve would reject.
+// The returned error joins one FieldError per problem.
+func (o ConvertOptions) Validate() error {
+	var errs []error
+	invalid := func(field, format string, args ...any) {
+		errs = append(errs, FieldError{Field: field, Reason: fmt.Sprintf(format, args...)})
+	}
+	if r := o.PageRange; r.From < 0 || r.To < 0 || (r.To != 0 && r.To < r.From) {
+		invalid("page_range", "must be two ascending page numbers, got %s", r)
+	}
+	if o.ForceOCR && o.DoOCR != nil && !*o.DoOCR {
+		invalid("force_ocr", "cannot be enabled when do_ocr is false")
+	}
+	if o.DocumentTimeout != nil && *o.DocumentTimeout <= 0 {
+		invalid("document_timeout", "must be positive, got %d", *o.DocumentTimeout)
+	}
+	if o.ImagesScale != nil && *o.ImagesScale <= 0 {
+		invalid("images_scale", "must be positive, got %v", *o.ImagesScale)
+	}
+	if t := o.PictureDescriptionAreaThreshold; t != nil && (*t < 0 || *t > 1) {
+		invalid("picture_description_area_threshold", "must be between 0 and 1, got %v", *t)
+	}
+	if o.PictureDescriptionLocal != nil && o.PictureDescriptionAPI != nil {
+		invalid("picture_description_api", "cannot be set together with picture_description_local")
+	}
+	if local := o.PictureDescriptionLocal; local != nil {
+		if local.RepoID == "" {
+			invalid("picture_description_local.repo_id", "is required")
+		}
+	}
+	if api := o.PictureDescriptionAPI; api != nil {
+		if api.URL == "" {
+			invalid("picture_description_api.url", "is required")
+		}
+	}
+	var vlmModels []string
+	if o.VLMPipelineModel != nil {
+		vlmModels = append(vlmModels, "vlm_pipeline_model")
+	}
+	if o.VLMPipelineModelLocal != nil {
+		vlmModels = append(vlmModels, "vlm_pipeline_model_local")
+	}
+	if o.VLMPipelineModelAPI != nil {
+		vlmModels = append(vlmModels, "vlm_pipeline_model_api")
+	}
+	for _, field := range vlmModels {
+		if o.Pipeline != PipelineVLM {
+			invalid(field, "requires pipeline %q, got %q", PipelineVLM, o.Pipeline)
+		}
+	}
+	if len(vlmModels) > 1 {
+		invalid(vlmModels[1], "cannot be set together with %s", vlmModels[0])
+	}
+	if local := o.VLMPipelineModelLocal; local != nil {
+		if local.RepoID == "" {
+			invalid("vlm_pipeline_model_local.repo_id", "is required")
+		}
+		if local.InferenceFramework == "" {
+			invalid("vlm_pipeline_model_local.inference_framework", "is required")
+		}
+		if local.ResponseFormat == "" {
+			invalid("vlm_pipeline_model_local.response_format", "is required")
+		}
+	}
+	if api := o.VLMPipelineModelAPI; api != nil {
+		if api.URL == "" {
+			invalid("vlm_pipeline_model_api.url", "is required")
+		}
+		if api.ResponseFormat == "" {
+			invalid("vlm_pipeline_model_api.response_format", "is required")
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (c *Client) validate(opts ConvertOptions) error {
+	if c.skipValidation {
+		return nil
+	}
+	err := opts.Validate()
+	if err != nil {
+		return fmt.Errorf("invalid convert options: %w", err)
+	}
+	return nil
+}